@@ -0,0 +1,239 @@
+package dbutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrStatementNotAllowed is returned when a statement is not in the allow-list
+var ErrStatementNotAllowed = errors.New("statement not in allow-list")
+
+// NormalizeSQL collapses whitespace and strips a trailing semicolon so that
+// formatting differences do not change a statement's identity
+func NormalizeSQL(sql string) string {
+	normalized := strings.Join(strings.Fields(sql), " ")
+	return strings.TrimSpace(strings.TrimSuffix(normalized, ";"))
+}
+
+// StatementHash returns the hex-encoded SHA-256 hash of the normalized statement
+func StatementHash(sql string) string {
+	sum := sha256.Sum256([]byte(NormalizeSQL(sql)))
+	return hex.EncodeToString(sum[:])
+}
+
+// StatementAllowList holds the normalized hashes of statements that may be executed
+type StatementAllowList struct {
+	mu     sync.RWMutex
+	hashes map[string]bool
+}
+
+// NewStatementAllowList creates an allow-list containing the given statements
+func NewStatementAllowList(statements ...string) *StatementAllowList {
+	l := &StatementAllowList{hashes: make(map[string]bool, len(statements))}
+	l.Register(statements...)
+	return l
+}
+
+// Register adds statements to the allow-list
+func (l *StatementAllowList) Register(statements ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, sql := range statements {
+		l.hashes[StatementHash(sql)] = true
+	}
+}
+
+// RegisterHashes adds precomputed statement hashes (see StatementHash) to the allow-list
+func (l *StatementAllowList) RegisterHashes(hashes ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, hash := range hashes {
+		l.hashes[hash] = true
+	}
+}
+
+// Allowed reports whether the statement is in the allow-list
+func (l *StatementAllowList) Allowed(sql string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.hashes[StatementHash(sql)]
+}
+
+// Len returns the number of allowed statements
+func (l *StatementAllowList) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.hashes)
+}
+
+// check returns an error if the statement is not allowed
+func (l *StatementAllowList) check(sql string) error {
+	if l.Allowed(sql) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrStatementNotAllowed, StatementHash(sql))
+}
+
+// AllowListDB wraps a DBTX and rejects statements that are not in the allow-list.
+// Because it implements DBTX, it can be passed to sqlc's New function so that
+// non-transactional queries are checked too:
+//
+//	allowList := dbutil.NewStatementAllowList(statements...) // e.g. loaded from your query files
+//	conn, err := dbutil.NewConnectionWithConfig(ctx, "", func(pool *pgxpool.Pool) *sqlc.Queries {
+//	    return sqlc.New(dbutil.NewAllowListDB(pool, allowList))
+//	}, &dbutil.Config{AllowList: allowList})
+//
+// Config.AllowList makes the Connection check its own Exec/Query/QueryRow and wrap
+// every transaction it begins, including the pgx.Tx given to sqlc's WithTx.
+type AllowListDB struct {
+	db        DBTX
+	allowList *StatementAllowList
+}
+
+// NewAllowListDB wraps db so that only statements in allowList are executed
+func NewAllowListDB(db DBTX, allowList *StatementAllowList) *AllowListDB {
+	return &AllowListDB{db: db, allowList: allowList}
+}
+
+// Exec executes the statement if it is allowed
+func (a *AllowListDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if err := a.allowList.check(sql); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return a.db.Exec(ctx, sql, args...)
+}
+
+// Query executes the query if it is allowed
+func (a *AllowListDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if err := a.allowList.check(sql); err != nil {
+		return nil, err
+	}
+	return a.db.Query(ctx, sql, args...)
+}
+
+// QueryRow executes the query if it is allowed; otherwise Scan returns the rejection error
+func (a *AllowListDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if err := a.allowList.check(sql); err != nil {
+		return errRow{err: err}
+	}
+	return a.db.QueryRow(ctx, sql, args...)
+}
+
+// errRow is a pgx.Row whose Scan always returns err
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(dest ...interface{}) error {
+	return r.err
+}
+
+// allowListTx wraps a pgx.Tx and rejects statements that are not in the allow-list.
+// CopyFrom, LargeObjects and Conn are passed through unchecked.
+type allowListTx struct {
+	pgx.Tx
+	allowList *StatementAllowList
+}
+
+// wrapAllowListTx wraps tx if an allow-list is configured
+func wrapAllowListTx(tx pgx.Tx, allowList *StatementAllowList) pgx.Tx {
+	if allowList == nil {
+		return tx
+	}
+	return &allowListTx{Tx: tx, allowList: allowList}
+}
+
+// Begin starts a pseudo nested transaction that is checked against the same allow-list
+func (t *allowListTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := t.Tx.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return wrapAllowListTx(tx, t.allowList), nil
+}
+
+// Exec executes the statement if it is allowed
+func (t *allowListTx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if err := t.allowList.check(sql); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return t.Tx.Exec(ctx, sql, args...)
+}
+
+// Query executes the query if it is allowed
+func (t *allowListTx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if err := t.allowList.check(sql); err != nil {
+		return nil, err
+	}
+	return t.Tx.Query(ctx, sql, args...)
+}
+
+// QueryRow executes the query if it is allowed; otherwise Scan returns the rejection error
+func (t *allowListTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if err := t.allowList.check(sql); err != nil {
+		return errRow{err: err}
+	}
+	return t.Tx.QueryRow(ctx, sql, args...)
+}
+
+// Prepare prepares the statement if it is allowed
+func (t *allowListTx) Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error) {
+	if err := t.allowList.check(sql); err != nil {
+		return nil, err
+	}
+	return t.Tx.Prepare(ctx, name, sql)
+}
+
+// SendBatch sends the batch only if every queued statement is allowed
+func (t *allowListTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	for _, q := range b.QueuedQueries {
+		if err := t.allowList.check(q.SQL); err != nil {
+			return errBatchResults{err: err}
+		}
+	}
+	return t.Tx.SendBatch(ctx, b)
+}
+
+// allowListBeginner starts transactions that are checked against the allow-list
+type allowListBeginner struct {
+	db        Beginner
+	allowList *StatementAllowList
+}
+
+// Begin starts a transaction wrapped with the allow-list
+func (b allowListBeginner) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := b.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return wrapAllowListTx(tx, b.allowList), nil
+}
+
+// errBatchResults is a pgx.BatchResults whose reads all return err
+type errBatchResults struct {
+	err error
+}
+
+func (r errBatchResults) Exec() (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, r.err
+}
+
+func (r errBatchResults) Query() (pgx.Rows, error) {
+	return nil, r.err
+}
+
+func (r errBatchResults) QueryRow() pgx.Row {
+	return errRow{err: r.err}
+}
+
+func (r errBatchResults) Close() error {
+	return r.err
+}
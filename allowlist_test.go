@@ -0,0 +1,233 @@
+package dbutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Compile-time checks that pgx database handles satisfy DBTX and Beginner
+var (
+	_ DBTX = (*pgxpool.Pool)(nil)
+	_ DBTX = (*pgx.Conn)(nil)
+	_ DBTX = (pgx.Tx)(nil)
+	_ DBTX = (*AllowListDB)(nil)
+	_ DBTX = (*Connection[*MockQuerier])(nil)
+
+	_ Beginner = (*pgxpool.Pool)(nil)
+	_ Beginner = (*pgx.Conn)(nil)
+	_ Beginner = (pgx.Tx)(nil)
+)
+
+// mockDBTX records executed statements
+type mockDBTX struct {
+	executed []string
+}
+
+func (m *mockDBTX) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	m.executed = append(m.executed, sql)
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (m *mockDBTX) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	m.executed = append(m.executed, sql)
+	return nil, nil
+}
+
+func (m *mockDBTX) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	m.executed = append(m.executed, sql)
+	return errRow{}
+}
+
+func TestNormalizeSQL(t *testing.T) {
+	sql := "SELECT id,\n\t  email\nFROM users\nWHERE id = $1;\n"
+	expected := "SELECT id, email FROM users WHERE id = $1"
+	if got := NormalizeSQL(sql); got != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, got)
+	}
+
+	if StatementHash(sql) != StatementHash(expected) {
+		t.Error("Expected formatting differences to produce the same hash")
+	}
+}
+
+func TestStatementAllowList(t *testing.T) {
+	list := NewStatementAllowList("SELECT id FROM users WHERE id = $1")
+
+	if !list.Allowed("SELECT id\nFROM users\nWHERE id = $1;") {
+		t.Error("Expected reformatted statement to be allowed")
+	}
+	if list.Allowed("SELECT * FROM users") {
+		t.Error("Expected unregistered statement to be rejected")
+	}
+
+	list.RegisterHashes(StatementHash("DELETE FROM users"))
+	if !list.Allowed("DELETE FROM users") {
+		t.Error("Expected statement registered by hash to be allowed")
+	}
+	if list.Len() != 2 {
+		t.Errorf("Expected 2 statements, got %d", list.Len())
+	}
+}
+
+func TestAllowListDB(t *testing.T) {
+	ctx := context.Background()
+	db := &mockDBTX{}
+	wrapped := NewAllowListDB(db, NewStatementAllowList("UPDATE users SET name = $1"))
+
+	if _, err := wrapped.Exec(ctx, "UPDATE users SET name = $1", "x"); err != nil {
+		t.Errorf("Expected allowed statement to execute, got %v", err)
+	}
+
+	if _, err := wrapped.Exec(ctx, "DROP TABLE users"); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected ErrStatementNotAllowed, got %v", err)
+	}
+	if _, err := wrapped.Query(ctx, "SELECT * FROM users"); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected ErrStatementNotAllowed, got %v", err)
+	}
+	if err := wrapped.QueryRow(ctx, "SELECT 1").Scan(); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected ErrStatementNotAllowed, got %v", err)
+	}
+
+	if len(db.executed) != 1 {
+		t.Errorf("Expected only the allowed statement to reach the database, got %v", db.executed)
+	}
+}
+
+// mockTx records statements that reach the transaction
+type mockTx struct {
+	pgx.Tx
+	executed []string
+}
+
+func (m *mockTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	return &mockTx{}, nil
+}
+
+func (m *mockTx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	m.executed = append(m.executed, sql)
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (m *mockTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	m.executed = append(m.executed, "batch")
+	return nil
+}
+
+// mockTxBeginner begins mockTx transactions
+type mockTxBeginner struct {
+	tx *mockTx
+}
+
+func (m *mockTxBeginner) Begin(ctx context.Context) (pgx.Tx, error) {
+	return m.tx, nil
+}
+
+func TestAllowListTx(t *testing.T) {
+	ctx := context.Background()
+	allowed := "UPDATE users SET email = $1 WHERE id = $2"
+	list := NewStatementAllowList(allowed)
+
+	if tx := wrapAllowListTx(&mockTx{}, nil); tx == nil {
+		t.Fatal("Expected tx to be returned unchanged without an allow-list")
+	} else if _, ok := tx.(*allowListTx); ok {
+		t.Error("Expected tx not to be wrapped without an allow-list")
+	}
+
+	inner := &mockTx{}
+	beginner := allowListBeginner{db: &mockTxBeginner{tx: inner}, allowList: list}
+	tx, err := beginner.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, allowed, "a@example.com", 1); err != nil {
+		t.Errorf("Expected allowed statement to run, got %v", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM users"); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected ErrStatementNotAllowed, got %v", err)
+	}
+	if _, err := tx.Query(ctx, "SELECT * FROM users"); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected ErrStatementNotAllowed from Query, got %v", err)
+	}
+	if err := tx.QueryRow(ctx, "SELECT 1").Scan(); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected ErrStatementNotAllowed from QueryRow, got %v", err)
+	}
+	if _, err := tx.Prepare(ctx, "stmt", "SELECT 1"); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected ErrStatementNotAllowed from Prepare, got %v", err)
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue(allowed, "a@example.com", 1)
+	batch.Queue("DELETE FROM users")
+	if err := tx.SendBatch(ctx, batch).Close(); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected ErrStatementNotAllowed from SendBatch, got %v", err)
+	}
+
+	if len(inner.executed) != 1 || inner.executed[0] != allowed {
+		t.Errorf("Expected only the allowed statement to reach the transaction, got %v", inner.executed)
+	}
+
+	// Nested transactions are checked too
+	nested, err := tx.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := nested.Exec(ctx, "DROP TABLE users"); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected nested transaction to be checked, got %v", err)
+	}
+}
+
+func TestConnectionAllowList(t *testing.T) {
+	ctx := context.Background()
+	list := NewStatementAllowList("SELECT 1")
+	conn := (&Connection[*MockQuerier]{queries: &MockQuerier{}}).WithAllowList(list)
+
+	_, err := conn.Exec(ctx, "DROP TABLE users")
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) || !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected *QueryError wrapping ErrStatementNotAllowed, got %v", err)
+	}
+	if _, err := conn.Query(ctx, "SELECT * FROM users"); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected ErrStatementNotAllowed from Query, got %v", err)
+	}
+	if err := conn.QueryRow(ctx, "SELECT * FROM users").Scan(); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected ErrStatementNotAllowed from QueryRow, got %v", err)
+	}
+	if _, err := conn.Execer().Exec(ctx, "DROP TABLE users"); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected Execer to be checked, got %v", err)
+	}
+	if _, ok := conn.Beginner().(allowListBeginner); !ok {
+		t.Errorf("Expected Beginner to wrap transactions, got %T", conn.Beginner())
+	}
+
+	// The allow-list survives other With* copies
+	if conn.WithMetrics(&testMetricsCollector{}).allowList != list || conn.WithHooks(nil).allowList != list {
+		t.Error("Expected allow-list to be kept by WithMetrics and WithHooks")
+	}
+
+	if allowListFromConfigs(&Config{AllowList: list}, nil) != list {
+		t.Error("Expected read config allow-list to be used when write config has none")
+	}
+}
+
+func TestConnectionWithLoggingAllowList(t *testing.T) {
+	// The pool connects lazily, so an unreachable server is fine here
+	list := NewStatementAllowList("SELECT 1")
+	conn, err := NewConnectionWithConfigAndLogging(context.Background(), "postgres://postgres@127.0.0.1:1/postgres?connect_timeout=1",
+		func(*pgxpool.Pool) *MockQuerier { return &MockQuerier{} },
+		&Config{AllowList: list},
+		&LoggingConfig{Logger: NewDefaultLogger(LogLevelError), LogConnections: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec(context.Background(), "DROP TABLE users"); !errors.Is(err, ErrStatementNotAllowed) {
+		t.Errorf("Expected ErrStatementNotAllowed, got %v", err)
+	}
+}
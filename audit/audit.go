@@ -0,0 +1,157 @@
+// Package audit records Create/Update/Delete operations into an audit table.
+//
+// Entries are written through any dbutil.Execer (a pgx.Tx, *pgxpool.Pool, or *pgx.Conn),
+// so passing the transaction used for the data change keeps the audit row and the
+// change itself atomic:
+//
+//	tx, queries, err := conn.BeginTransaction(ctx)
+//	...
+//	user, err := queries.UpdateUser(ctx, params)
+//	...
+//	err = recorder.Record(ctx, tx, audit.Entry{
+//	    Table:    "users",
+//	    RecordID: user.ID.String(),
+//	    Action:   audit.ActionUpdate,
+//	    Before:   before,
+//	    After:    user,
+//	})
+//	...
+//	err = tx.Commit(ctx)
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nhalm/dbutil"
+)
+
+// DefaultTable is the audit table used when NewRecorder is given an empty name.
+const DefaultTable = "audit_log"
+
+// Action identifies the kind of change being audited
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Entry describes a single audited change.
+// Before and After are marshalled to JSON; a nil value is stored as NULL.
+type Entry struct {
+	Table      string
+	RecordID   string
+	Action     Action
+	Actor      string // defaults to the actor stored with dbutil.WithActor
+	Before     interface{}
+	After      interface{}
+	OccurredAt time.Time // defaults to the recorder's clock
+}
+
+// Recorder writes audit entries into a single audit table
+type Recorder struct {
+	table string
+	clock dbutil.Clock
+}
+
+// NewRecorder creates a recorder writing to the given table.
+// The table name may be schema-qualified (e.g. "audit.changes").
+func NewRecorder(table string) *Recorder {
+	if table == "" {
+		table = DefaultTable
+	}
+	return &Recorder{table: table, clock: dbutil.SystemClock{}}
+}
+
+// WithClock returns a copy of the recorder that timestamps entries using clock.
+// A nil clock restores the system clock.
+func (r *Recorder) WithClock(clock dbutil.Clock) *Recorder {
+	if clock == nil {
+		clock = dbutil.SystemClock{}
+	}
+	return &Recorder{table: r.table, clock: clock}
+}
+
+// Table returns the audit table name
+func (r *Recorder) Table() string {
+	return r.table
+}
+
+// CreateTableSQL returns DDL creating the audit table expected by Record
+func (r *Recorder) CreateTableSQL() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id BIGSERIAL PRIMARY KEY,
+	table_name TEXT NOT NULL,
+	record_id TEXT NOT NULL,
+	action TEXT NOT NULL,
+	actor TEXT,
+	before JSONB,
+	after JSONB,
+	occurred_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`, r.quotedTable())
+}
+
+// Record inserts an audit entry using db, which should be the transaction performing the change
+func (r *Recorder) Record(ctx context.Context, db dbutil.Execer, entry Entry) error {
+	if entry.Table == "" {
+		return dbutil.NewValidationError("audit entry", "record", "table", "is required", nil)
+	}
+	switch entry.Action {
+	case ActionCreate, ActionUpdate, ActionDelete:
+	default:
+		return dbutil.NewValidationError("audit entry", "record", "action", fmt.Sprintf("unknown action %q", entry.Action), nil)
+	}
+
+	if entry.Actor == "" {
+		entry.Actor, _ = dbutil.ActorFromContext(ctx)
+	}
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = r.clock.Now()
+	}
+
+	before, err := marshalState(entry.Before)
+	if err != nil {
+		return dbutil.NewValidationError("audit entry", "record", "before", "cannot be marshalled to JSON", err)
+	}
+	after, err := marshalState(entry.After)
+	if err != nil {
+		return dbutil.NewValidationError("audit entry", "record", "after", "cannot be marshalled to JSON", err)
+	}
+
+	var actor *string
+	if entry.Actor != "" {
+		actor = &entry.Actor
+	}
+
+	if _, err := db.Exec(ctx, r.insertSQL(), entry.Table, entry.RecordID, string(entry.Action), actor, before, after, entry.OccurredAt); err != nil {
+		return dbutil.NewQueryError("", r.table, "record audit entry", err)
+	}
+	return nil
+}
+
+// insertSQL returns the statement inserting one audit entry
+func (r *Recorder) insertSQL() string {
+	return fmt.Sprintf(
+		"INSERT INTO %s (table_name, record_id, action, actor, before, after, occurred_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		r.quotedTable(),
+	)
+}
+
+// quotedTable returns the table name quoted as an identifier, honoring schema qualification
+func (r *Recorder) quotedTable() string {
+	return pgx.Identifier(strings.Split(r.table, ".")).Sanitize()
+}
+
+// marshalState encodes a before/after snapshot as JSON, keeping nil as SQL NULL
+func marshalState(v interface{}) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,165 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nhalm/dbutil"
+)
+
+// mockExecer records Exec calls for assertions
+type mockExecer struct {
+	sql  string
+	args []interface{}
+	err  error
+}
+
+func (m *mockExecer) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	m.sql = sql
+	m.args = args
+	return pgconn.NewCommandTag("INSERT 0 1"), m.err
+}
+
+func TestNewRecorder(t *testing.T) {
+	if NewRecorder("").Table() != DefaultTable {
+		t.Errorf("Expected default table '%s', got '%s'", DefaultTable, NewRecorder("").Table())
+	}
+
+	r := NewRecorder("audit.changes")
+	if !strings.Contains(r.CreateTableSQL(), `"audit"."changes"`) {
+		t.Errorf("Expected schema-qualified quoted table in DDL, got: %s", r.CreateTableSQL())
+	}
+}
+
+func TestRecord(t *testing.T) {
+	db := &mockExecer{}
+	r := NewRecorder("")
+	ctx := dbutil.WithActor(context.Background(), "user-42")
+	occurred := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	err := r.Record(ctx, db, Entry{
+		Table:      "users",
+		RecordID:   "123",
+		Action:     ActionUpdate,
+		Before:     map[string]string{"email": "old@example.com"},
+		After:      map[string]string{"email": "new@example.com"},
+		OccurredAt: occurred,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.HasPrefix(db.sql, `INSERT INTO "audit_log"`) {
+		t.Errorf("Expected insert into audit_log, got: %s", db.sql)
+	}
+	if len(db.args) != 7 {
+		t.Fatalf("Expected 7 args, got %d", len(db.args))
+	}
+	if db.args[0] != "users" || db.args[1] != "123" || db.args[2] != "update" {
+		t.Errorf("Unexpected args: %v", db.args[:3])
+	}
+	if actor, ok := db.args[3].(*string); !ok || actor == nil || *actor != "user-42" {
+		t.Errorf("Expected actor from context, got %v", db.args[3])
+	}
+	if string(db.args[4].([]byte)) != `{"email":"old@example.com"}` {
+		t.Errorf("Unexpected before JSON: %s", db.args[4])
+	}
+	if db.args[6] != occurred {
+		t.Errorf("Expected occurred_at %v, got %v", occurred, db.args[6])
+	}
+}
+
+func TestRecordActorFromDbutilContext(t *testing.T) {
+	db := &mockExecer{}
+	ctx := dbutil.WithActor(context.Background(), "user-7")
+
+	if err := NewRecorder("").Record(ctx, db, Entry{Table: "users", Action: ActionDelete}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if actor := db.args[3].(*string); actor == nil || *actor != "user-7" {
+		t.Errorf("Expected actor set with dbutil.WithActor, got %v", db.args[3])
+	}
+}
+
+func TestRecordNilStateAndNoActor(t *testing.T) {
+	db := &mockExecer{}
+
+	err := NewRecorder("").Record(context.Background(), db, Entry{
+		Table:    "users",
+		RecordID: "123",
+		Action:   ActionCreate,
+		After:    map[string]string{"email": "new@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if db.args[3].(*string) != nil {
+		t.Errorf("Expected NULL actor, got %v", db.args[3])
+	}
+	if db.args[4].([]byte) != nil {
+		t.Errorf("Expected NULL before state, got %s", db.args[4])
+	}
+	if db.args[6].(time.Time).IsZero() {
+		t.Error("Expected occurred_at to default to now")
+	}
+}
+
+func TestRecordWithClock(t *testing.T) {
+	db := &mockExecer{}
+	frozen := time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+	r := NewRecorder("").WithClock(dbutil.NewFrozenClock(frozen))
+
+	if err := r.Record(context.Background(), db, Entry{Table: "users", Action: ActionCreate}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if db.args[6] != frozen {
+		t.Errorf("Expected occurred_at %v from clock, got %v", frozen, db.args[6])
+	}
+	if r.Table() != DefaultTable {
+		t.Errorf("Expected WithClock to keep table '%s', got '%s'", DefaultTable, r.Table())
+	}
+
+	// A nil clock falls back to the system clock
+	if err := r.WithClock(nil).Record(context.Background(), db, Entry{Table: "users", Action: ActionCreate}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if occurred := db.args[6].(time.Time); occurred.IsZero() || occurred.Equal(frozen) {
+		t.Errorf("Expected occurred_at from the system clock, got %v", occurred)
+	}
+}
+
+func TestRecordValidation(t *testing.T) {
+	db := &mockExecer{}
+	r := NewRecorder("")
+
+	var validationErr *dbutil.ValidationError
+	if err := r.Record(context.Background(), db, Entry{Action: ActionCreate}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected ValidationError for missing table, got %v", err)
+	}
+	if err := r.Record(context.Background(), db, Entry{Table: "users", Action: "truncate"}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected ValidationError for unknown action, got %v", err)
+	}
+	if err := r.Record(context.Background(), db, Entry{Table: "users", Action: ActionCreate, After: make(chan int)}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected ValidationError for unmarshallable state, got %v", err)
+	}
+}
+
+func TestRecordExecError(t *testing.T) {
+	execErr := errors.New("connection reset")
+	db := &mockExecer{err: execErr}
+
+	err := NewRecorder("").Record(context.Background(), db, Entry{Table: "users", Action: ActionDelete})
+
+	var queryErr *dbutil.QueryError
+	if !errors.As(err, &queryErr) {
+		t.Fatalf("Expected *QueryError, got %T", err)
+	}
+	if !errors.Is(err, execErr) {
+		t.Error("Expected original error to be preserved")
+	}
+}
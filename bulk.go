@@ -0,0 +1,308 @@
+package dbutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrBulkWriterClosed is returned when writing to a closed BulkWriter
+var ErrBulkWriterClosed = errors.New("bulk writer is closed")
+
+// BulkWriteError is returned when a flush fails with a non-retryable error, such as a
+// constraint violation or a missing table. The batch is dropped from the writer, so
+// one bad batch cannot wedge it, and its rows are returned here for inspection.
+type BulkWriteError struct {
+	Rows [][]interface{}
+	Err  error
+}
+
+func (e *BulkWriteError) Error() string {
+	return fmt.Sprintf("bulk write dropped %d rows: %v", len(e.Rows), e.Err)
+}
+
+func (e *BulkWriteError) Unwrap() error {
+	return e.Err
+}
+
+// Default thresholds for BulkWriter
+const (
+	DefaultBulkMaxRows       = 1000
+	DefaultBulkFlushInterval = time.Second
+)
+
+// CopyFromer runs the COPY protocol. It is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+type CopyFromer interface {
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// BulkWriterConfig configures a BulkWriter
+type BulkWriterConfig[T any] struct {
+	// Table is the target table, optionally schema-qualified ("billing.events")
+	Table string
+	// Columns are the target columns, in the order returned by Row
+	Columns []string
+	// Row converts an item to its column values
+	Row func(T) []interface{}
+	// MaxRows flushes once this many rows are buffered (default DefaultBulkMaxRows).
+	// Writers block while 2*MaxRows rows are buffered or being flushed, which bounds memory.
+	MaxRows int
+	// FlushInterval flushes buffered rows at least this often (default DefaultBulkFlushInterval)
+	FlushInterval time.Duration
+	// Retry controls retries of transient flush failures (default DefaultRetryConfig)
+	Retry *RetryConfig
+	// Metrics, if set, records each flush as a query named "bulk_write <table>"
+	Metrics MetricsCollector
+	// Logger, if set, logs failed background flushes
+	Logger Logger
+	// OnDrop, if set, is called with every batch dropped after a non-retryable failure,
+	// including batches from background flushes that have no caller to return the error to
+	OnDrop func(err *BulkWriteError)
+}
+
+// BulkWriter buffers rows and writes them with COPY when the buffer is full or the
+// flush interval elapses. Rows from a flush that failed transiently (or was cancelled)
+// stay buffered and are retried by the next flush; rows from a non-retryable failure
+// are dropped and reported as a *BulkWriteError. Call Close to flush the remaining rows.
+type BulkWriter[T any] struct {
+	db        CopyFromer
+	table     pgx.Identifier
+	queryName string
+	cfg       BulkWriterConfig[T]
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	buf      [][]interface{}
+	inFlight int  // rows being written by the current flush
+	flushing bool // a flush is in progress; only one runs at a time
+	closed   bool
+
+	ctx    context.Context // cancelled by Close to abort a stuck background flush
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewBulkWriter creates a BulkWriter and starts its background flush loop
+func NewBulkWriter[T any](db CopyFromer, cfg BulkWriterConfig[T]) (*BulkWriter[T], error) {
+	if db == nil {
+		return nil, fmt.Errorf("database cannot be nil")
+	}
+	if cfg.Table == "" {
+		return nil, fmt.Errorf("table cannot be empty")
+	}
+	if len(cfg.Columns) == 0 {
+		return nil, fmt.Errorf("at least one column is required")
+	}
+	if cfg.Row == nil {
+		return nil, fmt.Errorf("row function cannot be nil")
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = DefaultBulkMaxRows
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultBulkFlushInterval
+	}
+	if cfg.Retry == nil {
+		cfg.Retry = DefaultRetryConfig()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &BulkWriter[T]{
+		db:        db,
+		table:     pgx.Identifier(strings.Split(cfg.Table, ".")),
+		queryName: "bulk_write " + cfg.Table,
+		cfg:       cfg,
+		buf:       make([][]interface{}, 0, cfg.MaxRows),
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mu)
+	go w.run()
+	return w, nil
+}
+
+// Write buffers an item. Once MaxRows rows are buffered the caller flushes them
+// synchronously; while the buffer is at its memory bound, Write blocks until a flush
+// completes. An error means the item was not buffered: either the writer is closed or
+// the buffer is full and flushing it failed. Other flush failures are logged.
+func (w *BulkWriter[T]) Write(ctx context.Context, item T) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+
+	row := w.cfg.Row(item)
+	if len(row) != len(w.cfg.Columns) {
+		return NewValidationError(w.cfg.Table, "bulk write", "row", fmt.Sprintf("expected %d values, got %d", len(w.cfg.Columns), len(row)), nil)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for {
+		if w.closed {
+			return ErrBulkWriterClosed
+		}
+		if len(w.buf)+w.inFlight < 2*w.cfg.MaxRows {
+			break
+		}
+		// At the memory bound: flush the full buffer ourselves, or wait for the running flush
+		if !w.flushing {
+			if err := w.flushLocked(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		w.cond.Wait()
+	}
+
+	w.buf = append(w.buf, row)
+	if len(w.buf) >= w.cfg.MaxRows && !w.flushing {
+		// The item is buffered either way, so the flush error is only logged
+		if err := w.flushLocked(ctx); err != nil {
+			w.logFlushError(ctx, err)
+		}
+	}
+	return nil
+}
+
+// Flush writes all buffered rows now, waiting for any flush already in progress
+func (w *BulkWriter[T]) Flush(ctx context.Context) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for w.flushing {
+		w.cond.Wait()
+	}
+	return w.flushLocked(ctx)
+}
+
+// Close stops the background flush loop, aborting a background flush in progress,
+// and writes the remaining rows using ctx
+func (w *BulkWriter[T]) Close(ctx context.Context) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.cond.Broadcast()
+	w.mu.Unlock()
+
+	w.cancel()
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return w.Flush(ctx)
+}
+
+// Buffered returns the number of rows waiting to be flushed, including rows being written
+func (w *BulkWriter[T]) Buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.buf) + w.inFlight
+}
+
+// run flushes on the configured interval until Close cancels w.ctx
+func (w *BulkWriter[T]) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			var err error
+			if !w.flushing {
+				err = w.flushLocked(w.ctx)
+			}
+			w.mu.Unlock()
+
+			if err != nil && w.ctx.Err() == nil {
+				w.logFlushError(w.ctx, err)
+			}
+		}
+	}
+}
+
+// logFlushError logs a flush failure whose rows were kept for the next flush
+func (w *BulkWriter[T]) logFlushError(ctx context.Context, err error) {
+	if w.cfg.Logger == nil {
+		return
+	}
+	w.cfg.Logger.Log(ctx, LogLevelError, "Bulk write flush failed", map[string]interface{}{
+		"table": w.cfg.Table,
+		"error": err.Error(),
+	})
+}
+
+// flushLocked writes the buffered rows. The caller must hold w.mu and no flush may be
+// in progress; w.mu is released while rows are written, so other writers can keep
+// buffering up to the memory bound. After a transient or cancelled failure the rows are
+// put back at the front of the buffer; after any other failure they are dropped and
+// returned in a *BulkWriteError.
+func (w *BulkWriter[T]) flushLocked(ctx context.Context) error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	rows := w.buf
+	w.buf = make([][]interface{}, 0, w.cfg.MaxRows)
+	w.inFlight = len(rows)
+	w.flushing = true
+	w.mu.Unlock()
+
+	err := w.write(ctx, rows)
+	keep := err != nil && (isRetryableError(err) || ctx.Err() != nil)
+	if err != nil && !keep {
+		dropped := &BulkWriteError{Rows: rows, Err: err}
+		if w.cfg.OnDrop != nil {
+			w.cfg.OnDrop(dropped)
+		}
+		err = dropped
+	}
+
+	w.mu.Lock()
+	if keep {
+		w.buf = append(rows, w.buf...)
+	}
+	w.inFlight = 0
+	w.flushing = false
+	w.cond.Broadcast()
+	return err
+}
+
+// write copies rows to the table, retrying transient failures
+func (w *BulkWriter[T]) write(ctx context.Context, rows [][]interface{}) error {
+	start := time.Now()
+	err := retryOperation(ctx, w.cfg.Retry, func(ctx context.Context) error {
+		_, err := w.db.CopyFrom(ctx, w.table, w.cfg.Columns, pgx.CopyFromRows(rows))
+		return err
+	})
+	if w.cfg.Metrics != nil {
+		w.cfg.Metrics.RecordQueryExecuted(w.queryName, time.Since(start), err)
+	}
+	if err != nil {
+		return NewQueryError("", w.cfg.Table, "bulk write", fmt.Errorf("%d rows: %w", len(rows), err))
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package dbutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Compile-time checks that pgx database handles satisfy CopyFromer
+var (
+	_ CopyFromer = (*pgxpool.Pool)(nil)
+	_ CopyFromer = (*pgx.Conn)(nil)
+	_ CopyFromer = (pgx.Tx)(nil)
+)
+
+// mockCopyFromer records copied batches and can fail a number of times
+type mockCopyFromer struct {
+	mu       sync.Mutex
+	batches  [][][]interface{}
+	failures int
+	err      error
+}
+
+func (m *mockCopyFromer) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.failures > 0 {
+		m.failures--
+		return 0, m.err
+	}
+
+	var rows [][]interface{}
+	for rowSrc.Next() {
+		values, err := rowSrc.Values()
+		if err != nil {
+			return 0, err
+		}
+		rows = append(rows, values)
+	}
+	m.batches = append(m.batches, rows)
+	return int64(len(rows)), nil
+}
+
+func (m *mockCopyFromer) rowCount() (batches, rows int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, b := range m.batches {
+		rows += len(b)
+	}
+	return len(m.batches), rows
+}
+
+type testEvent struct {
+	ID   int
+	Name string
+}
+
+func newTestBulkConfig() BulkWriterConfig[testEvent] {
+	return BulkWriterConfig[testEvent]{
+		Table:         "events",
+		Columns:       []string{"id", "name"},
+		Row:           func(e testEvent) []interface{} { return []interface{}{e.ID, e.Name} },
+		MaxRows:       3,
+		FlushInterval: time.Hour,
+		Retry:         &RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1},
+	}
+}
+
+func TestBulkWriterFlushesOnSize(t *testing.T) {
+	db := &mockCopyFromer{}
+	metrics := &testMetricsCollector{}
+	cfg := newTestBulkConfig()
+	cfg.Metrics = metrics
+
+	w, err := NewBulkWriter(db, cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 7; i++ {
+		if err := w.Write(ctx, testEvent{ID: i, Name: "event"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if batches, rows := db.rowCount(); batches != 2 || rows != 6 {
+		t.Errorf("Expected 2 batches with 6 rows, got %d batches with %d rows", batches, rows)
+	}
+	if w.Buffered() != 1 {
+		t.Errorf("Expected 1 buffered row, got %d", w.Buffered())
+	}
+
+	// Close flushes the remainder
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if batches, rows := db.rowCount(); batches != 3 || rows != 7 {
+		t.Errorf("Expected 3 batches with 7 rows, got %d batches with %d rows", batches, rows)
+	}
+	if metrics.QueriesExecuted != 3 {
+		t.Errorf("Expected 3 flushes recorded, got %d", metrics.QueriesExecuted)
+	}
+
+	if err := w.Write(ctx, testEvent{ID: 8}); !errors.Is(err, ErrBulkWriterClosed) {
+		t.Errorf("Expected ErrBulkWriterClosed, got %v", err)
+	}
+}
+
+func TestBulkWriterFlushesOnInterval(t *testing.T) {
+	db := &mockCopyFromer{}
+	cfg := newTestBulkConfig()
+	cfg.FlushInterval = 10 * time.Millisecond
+
+	w, err := NewBulkWriter(db, cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = w.Close(context.Background()) }()
+
+	if err := w.Write(context.Background(), testEvent{ID: 1, Name: "event"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, rows := db.rowCount(); rows == 1 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("Expected buffered row to be flushed by the interval")
+}
+
+func TestBulkWriterRetry(t *testing.T) {
+	ctx := context.Background()
+
+	// Transient failures are retried
+	db := &mockCopyFromer{failures: 2, err: errors.New("connection reset by peer")}
+	w, _ := NewBulkWriter(db, newTestBulkConfig())
+	_ = w.Write(ctx, testEvent{ID: 1})
+	if err := w.Flush(ctx); err != nil {
+		t.Errorf("Expected retry to succeed, got %v", err)
+	}
+	_ = w.Close(ctx)
+
+	// Permanent failures drop the batch and return it with the QueryError
+	db = &mockCopyFromer{failures: 1, err: errors.New("relation does not exist")}
+	w, _ = NewBulkWriter(db, newTestBulkConfig())
+	_ = w.Write(ctx, testEvent{ID: 1})
+	err := w.Flush(ctx)
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) || queryErr.Table != "events" {
+		t.Errorf("Expected *QueryError for events, got %v", err)
+	}
+	var writeErr *BulkWriteError
+	if !errors.As(err, &writeErr) || len(writeErr.Rows) != 1 {
+		t.Fatalf("Expected *BulkWriteError with 1 row, got %v", err)
+	}
+	if w.Buffered() != 0 {
+		t.Errorf("Expected dropped row not to stay buffered, got %d", w.Buffered())
+	}
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestBulkWriterKeepsRowsOnFailedFlush(t *testing.T) {
+	ctx := context.Background()
+	// Fails the first attempt and both retries with a transient error
+	db := &mockCopyFromer{failures: 3, err: errors.New("connection reset by peer")}
+
+	// MaxRows 3: the third write triggers a flush that fails
+	w, _ := NewBulkWriter(db, newTestBulkConfig())
+	for i := 0; i < 4; i++ {
+		if err := w.Write(ctx, testEvent{ID: i}); err != nil {
+			t.Fatalf("Expected write %d to be buffered, got %v", i, err)
+		}
+	}
+
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if len(db.batches) != 1 || len(db.batches[0]) != 4 {
+		t.Fatalf("Expected all 4 rows in one batch, got %v", db.batches)
+	}
+	for i, row := range db.batches[0] {
+		if row[0] != i {
+			t.Errorf("Expected rows in write order, got %v at %d", row[0], i)
+		}
+	}
+}
+
+func TestBulkWriterDropsPoisonBatch(t *testing.T) {
+	ctx := context.Background()
+	db := &mockCopyFromer{failures: 100, err: errors.New("duplicate key value violates unique constraint")}
+
+	var mu sync.Mutex
+	var dropped int
+	cfg := newTestBulkConfig()
+	cfg.OnDrop = func(err *BulkWriteError) {
+		mu.Lock()
+		dropped += len(err.Rows)
+		mu.Unlock()
+	}
+	w, _ := NewBulkWriter(db, cfg)
+
+	// A failing batch must not wedge the writer once 2*MaxRows rows were written
+	for i := 0; i < 10; i++ {
+		if err := w.Write(ctx, testEvent{ID: i}); err != nil {
+			t.Fatalf("Expected write %d to succeed, got %v", i, err)
+		}
+	}
+	if w.Buffered() != 1 {
+		t.Errorf("Expected 1 row buffered, got %d", w.Buffered())
+	}
+
+	var writeErr *BulkWriteError
+	if err := w.Close(ctx); !errors.As(err, &writeErr) || len(writeErr.Rows) != 1 {
+		t.Errorf("Expected *BulkWriteError with the last row, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if dropped != 10 {
+		t.Errorf("Expected 10 rows reported to OnDrop, got %d", dropped)
+	}
+}
+
+// blockingCopyFromer blocks every CopyFrom until released or cancelled
+type blockingCopyFromer struct {
+	mockCopyFromer
+	started chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingCopyFromer) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	select {
+	case m.started <- struct{}{}:
+	default:
+	}
+	select {
+	case <-m.release:
+		return m.mockCopyFromer.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func TestBulkWriterBoundsMemory(t *testing.T) {
+	db := &blockingCopyFromer{started: make(chan struct{}, 1), release: make(chan struct{})}
+	w, _ := NewBulkWriter[testEvent](db, newTestBulkConfig())
+	ctx := context.Background()
+
+	// Many concurrent writers while the first flush is stuck
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			_ = w.Write(ctx, testEvent{ID: id})
+		}(i)
+	}
+
+	<-db.started
+	time.Sleep(20 * time.Millisecond)
+	if n := w.Buffered(); n > 2*3 {
+		t.Errorf("Expected at most 6 rows held in memory, got %d", n)
+	}
+
+	close(db.release)
+	wg.Wait()
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, rows := db.rowCount(); rows != 20 {
+		t.Errorf("Expected 20 rows written, got %d", rows)
+	}
+}
+
+func TestBulkWriterCloseAbortsStuckFlush(t *testing.T) {
+	db := &blockingCopyFromer{started: make(chan struct{}, 1), release: make(chan struct{})}
+	cfg := newTestBulkConfig()
+	cfg.FlushInterval = 10 * time.Millisecond
+	w, _ := NewBulkWriter(db, cfg)
+
+	_ = w.Write(context.Background(), testEvent{ID: 1})
+	<-db.started
+
+	// The stuck background flush is cancelled and Close retries with its own context
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := w.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Close to give up with its context, got %v", err)
+	}
+	if w.Buffered() != 1 {
+		t.Errorf("Expected unwritten row to stay buffered, got %d", w.Buffered())
+	}
+}
+
+func TestNewBulkWriterValidation(t *testing.T) {
+	cfg := newTestBulkConfig()
+	cfg.Columns = nil
+	if _, err := NewBulkWriter(&mockCopyFromer{}, cfg); err == nil {
+		t.Error("Expected error for missing columns")
+	}
+
+	if _, err := NewBulkWriter[testEvent](nil, newTestBulkConfig()); err == nil {
+		t.Error("Expected error for nil database")
+	}
+
+	w, _ := NewBulkWriter(&mockCopyFromer{}, newTestBulkConfig())
+	defer func() { _ = w.Close(context.Background()) }()
+
+	w.cfg.Row = func(e testEvent) []interface{} { return []interface{}{e.ID} }
+	var validationErr *ValidationError
+	if err := w.Write(context.Background(), testEvent{ID: 1}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected *ValidationError for wrong column count, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package dbutil
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time for timestamps set client-side
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is a Clock backed by time.Now
+type SystemClock struct{}
+
+// Now returns time.Now()
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FrozenClock is a Clock that returns a fixed time until it is changed, for deterministic tests
+type FrozenClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+// NewFrozenClock creates a clock frozen at t
+func NewFrozenClock(t time.Time) *FrozenClock {
+	return &FrozenClock{t: t}
+}
+
+// Now returns the frozen time
+func (c *FrozenClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+// Set freezes the clock at t
+func (c *FrozenClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = t
+}
+
+// Advance moves the frozen time forward by d
+func (c *FrozenClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
@@ -0,0 +1,34 @@
+package dbutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSystemClock(t *testing.T) {
+	before := time.Now()
+	now := SystemClock{}.Now()
+	if now.Before(before) {
+		t.Errorf("Expected system clock to return the current time, got %v", now)
+	}
+}
+
+func TestFrozenClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFrozenClock(start)
+
+	if !clock.Now().Equal(start) {
+		t.Errorf("Expected %v, got %v", start, clock.Now())
+	}
+
+	clock.Advance(time.Hour)
+	if !clock.Now().Equal(start.Add(time.Hour)) {
+		t.Errorf("Expected %v, got %v", start.Add(time.Hour), clock.Now())
+	}
+
+	later := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock.Set(later)
+	if !clock.Now().Equal(later) {
+		t.Errorf("Expected %v, got %v", later, clock.Now())
+	}
+}
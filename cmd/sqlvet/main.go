@@ -0,0 +1,51 @@
+// Command sqlvet reports database calls whose SQL is built by string concatenation
+// or fmt formatting. It exits with status 1 when any findings are reported.
+//
+// Usage:
+//
+//	sqlvet [-tests] [packages]
+//
+// Packages are directories; a trailing "/..." checks the directory recursively.
+// With no arguments, "./..." is checked.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nhalm/dbutil/sqlvet"
+)
+
+func main() {
+	includeTests := flag.Bool("tests", false, "also check _test.go files")
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	found := 0
+	for _, pattern := range patterns {
+		root, recursive := strings.CutSuffix(pattern, "/...")
+		if root == "" {
+			root = "."
+		}
+
+		diags, err := sqlvet.CheckTree(root, recursive, *includeTests)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "sqlvet: %v\n", err)
+			os.Exit(2)
+		}
+		for _, d := range diags {
+			fmt.Println(d)
+		}
+		found += len(diags)
+	}
+
+	if found > 0 {
+		os.Exit(1)
+	}
+}
@@ -31,9 +31,11 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -42,12 +44,40 @@ type Querier interface {
 	WithTx(tx pgx.Tx) Querier
 }
 
+// Execer executes statements that do not return rows.
+// It is satisfied by *pgxpool.Pool, *pgx.Conn, pgx.Tx and *Connection.
+type Execer interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+// Queryer executes statements that return rows.
+// It is satisfied by *pgxpool.Pool, *pgx.Conn, pgx.Tx and *Connection.
+type Queryer interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// Beginner starts transactions.
+// It is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx (which starts a savepoint).
+type Beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// DBTX is the database interface sqlc-generated code runs against.
+// It is satisfied by *pgxpool.Pool, *pgx.Conn, pgx.Tx and *Connection.
+type DBTX interface {
+	Execer
+	Queryer
+}
+
 // Connection represents a database connection with sqlc queries
 type Connection[T Querier] struct {
-	pool    *pgxpool.Pool
-	queries T
-	metrics MetricsCollector
-	hooks   *ConnectionHooks
+	pool      *pgxpool.Pool
+	queries   T
+	metrics   MetricsCollector
+	hooks     *ConnectionHooks
+	server    *serverInfo
+	allowList *StatementAllowList
 }
 
 // Config holds configuration options for database connections
@@ -59,6 +89,22 @@ type Config struct {
 	OnConnect       func(*pgx.Conn) error
 	OnDisconnect    func(*pgx.Conn)
 	Hooks           *ConnectionHooks
+
+	// QueryExecMode overrides pgx's default query exec mode (zero keeps the DSN or pgx default).
+	// QueryExecModeCacheStatement and QueryExecModeCacheDescribe avoid a Describe round trip
+	// on every execution of the same SQL.
+	QueryExecMode pgx.QueryExecMode
+	// StatementCacheCapacity sets the per-connection prepared statement cache size (zero keeps the default)
+	StatementCacheCapacity int
+	// DescriptionCacheCapacity sets the per-connection statement description cache size (zero keeps the default)
+	DescriptionCacheCapacity int
+
+	// AllowList, if set, restricts Connection.Exec/Query/QueryRow and every transaction
+	// begun by the connection to registered statements (see StatementAllowList).
+	// Queries() runs on whatever newQueriesFunc builds, so wrap the pool with NewAllowListDB
+	// there. Statements dbutil generates itself (RefreshMaterializedView, ServerVersion,
+	// InstalledExtensions, TruncateTables) and CopyFrom, which carries no SQL text, are not checked.
+	AllowList *StatementAllowList
 }
 
 // TransactionFunc is a function that executes within a transaction
@@ -166,6 +212,11 @@ func createPoolWithConfig(ctx context.Context, dsn string, cfg *Config) (*pgxpoo
 		return nil, err
 	}
 
+	// SearchPath also applies when an explicit DSN is given
+	if cfg != nil && cfg.SearchPath != "" {
+		config.ConnConfig.RuntimeParams["search_path"] = cfg.SearchPath
+	}
+
 	// Set default values
 	config.MaxConns = 10
 	config.MinConns = 1
@@ -182,6 +233,7 @@ func createPoolWithConfig(ctx context.Context, dsn string, cfg *Config) (*pgxpoo
 		if cfg.MaxConnLifetime > 0 {
 			config.MaxConnLifetime = cfg.MaxConnLifetime
 		}
+		applyStatementCacheConfig(config.ConnConfig, cfg)
 		if cfg.OnConnect != nil || cfg.Hooks != nil {
 			config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
 				// Execute individual OnConnect callback
@@ -216,6 +268,19 @@ func createPoolWithConfig(ctx context.Context, dsn string, cfg *Config) (*pgxpoo
 	return pgxpool.NewWithConfig(ctx, config)
 }
 
+// applyStatementCacheConfig copies the query exec mode and cache sizes from cfg
+func applyStatementCacheConfig(connConfig *pgx.ConnConfig, cfg *Config) {
+	if cfg.QueryExecMode != 0 {
+		connConfig.DefaultQueryExecMode = cfg.QueryExecMode
+	}
+	if cfg.StatementCacheCapacity > 0 {
+		connConfig.StatementCacheCapacity = cfg.StatementCacheCapacity
+	}
+	if cfg.DescriptionCacheCapacity > 0 {
+		connConfig.DescriptionCacheCapacity = cfg.DescriptionCacheCapacity
+	}
+}
+
 // NewConnectionWithConfig initializes a new pgxpool.Pool connection with configuration options
 func NewConnectionWithConfig[T Querier](ctx context.Context, dsn string, newQueriesFunc func(*pgxpool.Pool) T, cfg *Config) (*Connection[T], error) {
 	pool, err := createPoolWithConfig(ctx, dsn, cfg)
@@ -224,15 +289,19 @@ func NewConnectionWithConfig[T Querier](ctx context.Context, dsn string, newQuer
 	}
 
 	hooks := (*ConnectionHooks)(nil)
-	if cfg != nil && cfg.Hooks != nil {
+	allowList := (*StatementAllowList)(nil)
+	if cfg != nil {
 		hooks = cfg.Hooks
+		allowList = cfg.AllowList
 	}
 
 	return &Connection[T]{
-		pool:    pool,
-		queries: newQueriesFunc(pool),
-		metrics: nil,
-		hooks:   hooks,
+		pool:      pool,
+		queries:   newQueriesFunc(pool),
+		metrics:   nil,
+		hooks:     hooks,
+		server:    &serverInfo{},
+		allowList: allowList,
 	}, nil
 }
 
@@ -241,6 +310,33 @@ func (c *Connection[T]) GetDB() *pgxpool.Pool {
 	return c.pool
 }
 
+// Execer returns the underlying pool as an Execer, checked against the allow-list if one is set
+func (c *Connection[T]) Execer() Execer {
+	return c.DBTX()
+}
+
+// Queryer returns the underlying pool as a Queryer, checked against the allow-list if one is set
+func (c *Connection[T]) Queryer() Queryer {
+	return c.DBTX()
+}
+
+// Beginner returns the underlying pool as a Beginner whose transactions are checked
+// against the allow-list if one is set
+func (c *Connection[T]) Beginner() Beginner {
+	if c.allowList != nil {
+		return allowListBeginner{db: c.pool, allowList: c.allowList}
+	}
+	return c.pool
+}
+
+// DBTX returns the underlying pool as a DBTX, checked against the allow-list if one is set
+func (c *Connection[T]) DBTX() DBTX {
+	if c.allowList != nil {
+		return NewAllowListDB(c.pool, c.allowList)
+	}
+	return c.pool
+}
+
 // Queries returns the cached sqlc queries instance for this connection
 func (c *Connection[T]) Queries() T {
 	return c.queries
@@ -257,9 +353,9 @@ func (c *Connection[T]) WithTransaction(ctx context.Context, fn TransactionFunc[
 		return fmt.Errorf("transaction function cannot be nil")
 	}
 
-	tx, err := c.pool.Begin(ctx)
+	tx, err := c.begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return NewQueryError("", "", "begin transaction", err)
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
@@ -281,7 +377,7 @@ func (c *Connection[T]) WithTransaction(ctx context.Context, fn TransactionFunc[
 
 	// Commit the transaction
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return NewQueryError("", "", "commit transaction", err)
 	}
 
 	return nil
@@ -291,21 +387,55 @@ func (c *Connection[T]) WithTransaction(ctx context.Context, fn TransactionFunc[
 // The caller is responsible for committing or rolling back the transaction.
 // This is useful for more complex transaction management scenarios.
 func (c *Connection[T]) BeginTransaction(ctx context.Context) (pgx.Tx, T, error) {
-	tx, err := c.pool.Begin(ctx)
+	tx, err := c.begin(ctx)
 	if err != nil {
-		return nil, *new(T), fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, *new(T), NewQueryError("", "", "begin transaction", err)
 	}
 
 	txQueries := c.queries.WithTx(tx)
 	return tx, txQueries.(T), nil
 }
 
+// begin starts a transaction on the pool, wrapped with the allow-list if one is set
+func (c *Connection[T]) begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return wrapAllowListTx(tx, c.allowList), nil
+}
+
 // HealthCheck performs a simple health check by pinging the database
 func (c *Connection[T]) HealthCheck(ctx context.Context) error {
 	if ctx == nil {
 		return fmt.Errorf("context cannot be nil")
 	}
-	return c.pool.Ping(ctx)
+	if err := c.pool.Ping(ctx); err != nil {
+		return NewQueryError("", "", "ping database", err)
+	}
+	return nil
+}
+
+// RefreshMaterializedView runs REFRESH MATERIALIZED VIEW for the given view.
+// The name may be schema-qualified. With concurrently set, readers are not blocked
+// during the refresh; PostgreSQL then requires a unique index on the view.
+func (c *Connection[T]) RefreshMaterializedView(ctx context.Context, name string, concurrently bool) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+	if _, err := c.pool.Exec(ctx, refreshMaterializedViewSQL(name, concurrently)); err != nil {
+		return NewQueryError("", name, "refresh materialized view", err)
+	}
+	return nil
+}
+
+// refreshMaterializedViewSQL builds the REFRESH MATERIALIZED VIEW statement
+func refreshMaterializedViewSQL(name string, concurrently bool) string {
+	sql := "REFRESH MATERIALIZED VIEW "
+	if concurrently {
+		sql += "CONCURRENTLY "
+	}
+	return sql + pgx.Identifier(strings.Split(name, ".")).Sanitize()
 }
 
 // IsReady checks if the database connection is ready to accept queries
@@ -321,20 +451,37 @@ func (c *Connection[T]) Stats() *pgxpool.Stat {
 // WithMetrics returns a new connection with metrics collection enabled
 func (c *Connection[T]) WithMetrics(metrics MetricsCollector) *Connection[T] {
 	return &Connection[T]{
-		pool:    c.pool,
-		queries: c.queries,
-		metrics: metrics,
-		hooks:   c.hooks,
+		pool:      c.pool,
+		queries:   c.queries,
+		metrics:   metrics,
+		hooks:     c.hooks,
+		server:    c.server,
+		allowList: c.allowList,
 	}
 }
 
 // WithHooks returns a new connection with hooks enabled
 func (c *Connection[T]) WithHooks(hooks *ConnectionHooks) *Connection[T] {
 	return &Connection[T]{
-		pool:    c.pool,
-		queries: c.queries,
-		metrics: c.metrics,
-		hooks:   hooks,
+		pool:      c.pool,
+		queries:   c.queries,
+		metrics:   c.metrics,
+		hooks:     hooks,
+		server:    c.server,
+		allowList: c.allowList,
+	}
+}
+
+// WithAllowList returns a new connection that only executes statements in allowList
+// through Exec/Query/QueryRow, the interface accessors and its transactions
+func (c *Connection[T]) WithAllowList(allowList *StatementAllowList) *Connection[T] {
+	return &Connection[T]{
+		pool:      c.pool,
+		queries:   c.queries,
+		metrics:   c.metrics,
+		hooks:     c.hooks,
+		server:    c.server,
+		allowList: allowList,
 	}
 }
 
@@ -348,10 +495,12 @@ func (c *Connection[T]) AddHook(hook *ConnectionHooks) *Connection[T] {
 	}
 
 	return &Connection[T]{
-		pool:    c.pool,
-		queries: c.queries,
-		metrics: c.metrics,
-		hooks:   combinedHooks,
+		pool:      c.pool,
+		queries:   c.queries,
+		metrics:   c.metrics,
+		hooks:     combinedHooks,
+		server:    c.server,
+		allowList: c.allowList,
 	}
 }
 
@@ -0,0 +1,40 @@
+package dbutil
+
+import "context"
+
+type actorContextKey struct{}
+
+type tenantContextKey struct{}
+
+// WithActor returns a context carrying the acting user (e.g. a user ID).
+// It is read by the audit package and can be used to populate created_by/updated_by columns.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in the context, if any
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorContextKey{}).(string)
+	return actor, ok && actor != ""
+}
+
+// WithTenant returns a context carrying the current tenant ID, for populating
+// and filtering tenant_id columns
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ID stored in the context, if any
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// RequireTenant returns the tenant ID stored in the context or a ValidationError if none is set
+func RequireTenant(ctx context.Context) (string, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return "", NewValidationError("context", "read", "tenant", "no tenant set in context", nil)
+	}
+	return tenant, nil
+}
@@ -0,0 +1,42 @@
+package dbutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestActorContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := ActorFromContext(ctx); ok {
+		t.Error("Expected no actor in empty context")
+	}
+
+	ctx = WithActor(ctx, "user-42")
+	actor, ok := ActorFromContext(ctx)
+	if !ok || actor != "user-42" {
+		t.Errorf("Expected actor 'user-42', got '%s'", actor)
+	}
+
+	if _, ok := ActorFromContext(WithActor(ctx, "")); ok {
+		t.Error("Expected empty actor to be treated as unset")
+	}
+}
+
+func TestTenantContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := TenantFromContext(ctx); ok {
+		t.Error("Expected no tenant in empty context")
+	}
+
+	var validationErr *ValidationError
+	if _, err := RequireTenant(ctx); !errors.As(err, &validationErr) {
+		t.Errorf("Expected ValidationError without tenant, got %v", err)
+	}
+
+	ctx = WithTenant(ctx, "acme")
+	tenant, err := RequireTenant(ctx)
+	if err != nil || tenant != "acme" {
+		t.Errorf("Expected tenant 'acme', got '%s' (err=%v)", tenant, err)
+	}
+}
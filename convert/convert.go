@@ -0,0 +1,311 @@
+// Package convert provides generics-based helpers for converting between pgtype
+// values and plain Go values or pointers.
+//
+// Every supported pgtype follows the same naming scheme:
+//
+//	ToText(s)       // string       -> pgtype.Text (always valid)
+//	ToTextPtr(p)    // *string      -> pgtype.Text (nil becomes NULL)
+//	FromText(t)     // pgtype.Text  -> string      (NULL becomes "")
+//	TextPtr(t)      // pgtype.Text  -> *string     (NULL becomes nil)
+//
+// These helpers remove the repetitive Valid-flag juggling around pgtype values in
+// application code that works with sqlc-generated structs.
+package convert
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Ptr returns a pointer to a copy of v.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Deref returns the value p points to, or the zero value of T if p is nil.
+func Deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+// DerefOr returns the value p points to, or def if p is nil.
+func DerefOr[T any](p *T, def T) T {
+	if p == nil {
+		return def
+	}
+	return *p
+}
+
+// toNullable converts a pointer into a pgtype value using wrap.
+// A nil pointer yields the zero pgtype value, which has Valid=false (NULL).
+func toNullable[T, P any](v *T, wrap func(T) P) P {
+	if v == nil {
+		var null P
+		return null
+	}
+	return wrap(*v)
+}
+
+// fromNullable returns a pointer to v when valid is true, or nil otherwise.
+func fromNullable[T any](v T, valid bool) *T {
+	if !valid {
+		return nil
+	}
+	return &v
+}
+
+// valueOrZero returns v when valid is true, or the zero value of T otherwise.
+func valueOrZero[T any](v T, valid bool) T {
+	if !valid {
+		var zero T
+		return zero
+	}
+	return v
+}
+
+// --- Text ---
+
+// ToText converts a string to a valid pgtype.Text.
+func ToText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: true}
+}
+
+// ToTextPtr converts a *string to pgtype.Text. A nil pointer becomes NULL.
+func ToTextPtr(s *string) pgtype.Text {
+	return toNullable(s, ToText)
+}
+
+// FromText converts a pgtype.Text to a string. NULL becomes "".
+func FromText(t pgtype.Text) string {
+	return valueOrZero(t.String, t.Valid)
+}
+
+// TextPtr converts a pgtype.Text to a *string. NULL becomes nil.
+func TextPtr(t pgtype.Text) *string {
+	return fromNullable(t.String, t.Valid)
+}
+
+// --- Int2 ---
+
+// ToInt2 converts an int16 to a valid pgtype.Int2.
+func ToInt2(i int16) pgtype.Int2 {
+	return pgtype.Int2{Int16: i, Valid: true}
+}
+
+// ToInt2Ptr converts an *int16 to pgtype.Int2. A nil pointer becomes NULL.
+func ToInt2Ptr(i *int16) pgtype.Int2 {
+	return toNullable(i, ToInt2)
+}
+
+// FromInt2 converts a pgtype.Int2 to an int16. NULL becomes 0.
+func FromInt2(i pgtype.Int2) int16 {
+	return valueOrZero(i.Int16, i.Valid)
+}
+
+// Int2Ptr converts a pgtype.Int2 to an *int16. NULL becomes nil.
+func Int2Ptr(i pgtype.Int2) *int16 {
+	return fromNullable(i.Int16, i.Valid)
+}
+
+// --- Int4 ---
+
+// ToInt4 converts an int32 to a valid pgtype.Int4.
+func ToInt4(i int32) pgtype.Int4 {
+	return pgtype.Int4{Int32: i, Valid: true}
+}
+
+// ToInt4Ptr converts an *int32 to pgtype.Int4. A nil pointer becomes NULL.
+func ToInt4Ptr(i *int32) pgtype.Int4 {
+	return toNullable(i, ToInt4)
+}
+
+// FromInt4 converts a pgtype.Int4 to an int32. NULL becomes 0.
+func FromInt4(i pgtype.Int4) int32 {
+	return valueOrZero(i.Int32, i.Valid)
+}
+
+// Int4Ptr converts a pgtype.Int4 to an *int32. NULL becomes nil.
+func Int4Ptr(i pgtype.Int4) *int32 {
+	return fromNullable(i.Int32, i.Valid)
+}
+
+// --- Int8 ---
+
+// ToInt8 converts an int64 to a valid pgtype.Int8.
+func ToInt8(i int64) pgtype.Int8 {
+	return pgtype.Int8{Int64: i, Valid: true}
+}
+
+// ToInt8Ptr converts an *int64 to pgtype.Int8. A nil pointer becomes NULL.
+func ToInt8Ptr(i *int64) pgtype.Int8 {
+	return toNullable(i, ToInt8)
+}
+
+// FromInt8 converts a pgtype.Int8 to an int64. NULL becomes 0.
+func FromInt8(i pgtype.Int8) int64 {
+	return valueOrZero(i.Int64, i.Valid)
+}
+
+// Int8Ptr converts a pgtype.Int8 to an *int64. NULL becomes nil.
+func Int8Ptr(i pgtype.Int8) *int64 {
+	return fromNullable(i.Int64, i.Valid)
+}
+
+// --- Float4 ---
+
+// ToFloat4 converts a float32 to a valid pgtype.Float4.
+func ToFloat4(f float32) pgtype.Float4 {
+	return pgtype.Float4{Float32: f, Valid: true}
+}
+
+// ToFloat4Ptr converts a *float32 to pgtype.Float4. A nil pointer becomes NULL.
+func ToFloat4Ptr(f *float32) pgtype.Float4 {
+	return toNullable(f, ToFloat4)
+}
+
+// FromFloat4 converts a pgtype.Float4 to a float32. NULL becomes 0.
+func FromFloat4(f pgtype.Float4) float32 {
+	return valueOrZero(f.Float32, f.Valid)
+}
+
+// Float4Ptr converts a pgtype.Float4 to a *float32. NULL becomes nil.
+func Float4Ptr(f pgtype.Float4) *float32 {
+	return fromNullable(f.Float32, f.Valid)
+}
+
+// --- Float8 ---
+
+// ToFloat8 converts a float64 to a valid pgtype.Float8.
+func ToFloat8(f float64) pgtype.Float8 {
+	return pgtype.Float8{Float64: f, Valid: true}
+}
+
+// ToFloat8Ptr converts a *float64 to pgtype.Float8. A nil pointer becomes NULL.
+func ToFloat8Ptr(f *float64) pgtype.Float8 {
+	return toNullable(f, ToFloat8)
+}
+
+// FromFloat8 converts a pgtype.Float8 to a float64. NULL becomes 0.
+func FromFloat8(f pgtype.Float8) float64 {
+	return valueOrZero(f.Float64, f.Valid)
+}
+
+// Float8Ptr converts a pgtype.Float8 to a *float64. NULL becomes nil.
+func Float8Ptr(f pgtype.Float8) *float64 {
+	return fromNullable(f.Float64, f.Valid)
+}
+
+// --- Bool ---
+
+// ToBool converts a bool to a valid pgtype.Bool.
+func ToBool(b bool) pgtype.Bool {
+	return pgtype.Bool{Bool: b, Valid: true}
+}
+
+// ToBoolPtr converts a *bool to pgtype.Bool. A nil pointer becomes NULL.
+func ToBoolPtr(b *bool) pgtype.Bool {
+	return toNullable(b, ToBool)
+}
+
+// FromBool converts a pgtype.Bool to a bool. NULL becomes false.
+func FromBool(b pgtype.Bool) bool {
+	return valueOrZero(b.Bool, b.Valid)
+}
+
+// BoolPtr converts a pgtype.Bool to a *bool. NULL becomes nil.
+func BoolPtr(b pgtype.Bool) *bool {
+	return fromNullable(b.Bool, b.Valid)
+}
+
+// --- Timestamptz ---
+
+// ToTimestamptz converts a time.Time to a valid pgtype.Timestamptz.
+func ToTimestamptz(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: true}
+}
+
+// ToTimestamptzPtr converts a *time.Time to pgtype.Timestamptz. A nil pointer becomes NULL.
+func ToTimestamptzPtr(t *time.Time) pgtype.Timestamptz {
+	return toNullable(t, ToTimestamptz)
+}
+
+// FromTimestamptz converts a pgtype.Timestamptz to a time.Time. NULL becomes the zero time.
+func FromTimestamptz(ts pgtype.Timestamptz) time.Time {
+	return valueOrZero(ts.Time, ts.Valid)
+}
+
+// TimestamptzPtr converts a pgtype.Timestamptz to a *time.Time. NULL becomes nil.
+func TimestamptzPtr(ts pgtype.Timestamptz) *time.Time {
+	return fromNullable(ts.Time, ts.Valid)
+}
+
+// --- Timestamp ---
+
+// ToTimestamp converts a time.Time to a valid pgtype.Timestamp.
+func ToTimestamp(t time.Time) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: t, Valid: true}
+}
+
+// ToTimestampPtr converts a *time.Time to pgtype.Timestamp. A nil pointer becomes NULL.
+func ToTimestampPtr(t *time.Time) pgtype.Timestamp {
+	return toNullable(t, ToTimestamp)
+}
+
+// FromTimestamp converts a pgtype.Timestamp to a time.Time. NULL becomes the zero time.
+func FromTimestamp(ts pgtype.Timestamp) time.Time {
+	return valueOrZero(ts.Time, ts.Valid)
+}
+
+// TimestampPtr converts a pgtype.Timestamp to a *time.Time. NULL becomes nil.
+func TimestampPtr(ts pgtype.Timestamp) *time.Time {
+	return fromNullable(ts.Time, ts.Valid)
+}
+
+// --- Date ---
+
+// ToDate converts a time.Time to a valid pgtype.Date.
+func ToDate(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: t, Valid: true}
+}
+
+// ToDatePtr converts a *time.Time to pgtype.Date. A nil pointer becomes NULL.
+func ToDatePtr(t *time.Time) pgtype.Date {
+	return toNullable(t, ToDate)
+}
+
+// FromDate converts a pgtype.Date to a time.Time. NULL becomes the zero time.
+func FromDate(d pgtype.Date) time.Time {
+	return valueOrZero(d.Time, d.Valid)
+}
+
+// DatePtr converts a pgtype.Date to a *time.Time. NULL becomes nil.
+func DatePtr(d pgtype.Date) *time.Time {
+	return fromNullable(d.Time, d.Valid)
+}
+
+// --- UUID ---
+
+// ToUUID converts a uuid.UUID to a valid pgtype.UUID.
+func ToUUID(id uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: id, Valid: true}
+}
+
+// ToUUIDPtr converts a *uuid.UUID to pgtype.UUID. A nil pointer becomes NULL.
+func ToUUIDPtr(id *uuid.UUID) pgtype.UUID {
+	return toNullable(id, ToUUID)
+}
+
+// FromUUID converts a pgtype.UUID to a uuid.UUID. NULL becomes uuid.Nil.
+func FromUUID(id pgtype.UUID) uuid.UUID {
+	return valueOrZero(uuid.UUID(id.Bytes), id.Valid)
+}
+
+// UUIDPtr converts a pgtype.UUID to a *uuid.UUID. NULL becomes nil.
+func UUIDPtr(id pgtype.UUID) *uuid.UUID {
+	return fromNullable(uuid.UUID(id.Bytes), id.Valid)
+}
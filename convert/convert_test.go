@@ -0,0 +1,144 @@
+package convert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestPtrAndDeref(t *testing.T) {
+	p := Ptr(42)
+	if p == nil || *p != 42 {
+		t.Errorf("Expected pointer to 42, got %v", p)
+	}
+
+	if Deref(p) != 42 {
+		t.Errorf("Expected 42, got %v", Deref(p))
+	}
+
+	var nilPtr *int
+	if Deref(nilPtr) != 0 {
+		t.Errorf("Expected zero value for nil pointer, got %v", Deref(nilPtr))
+	}
+
+	if DerefOr(nilPtr, 7) != 7 {
+		t.Errorf("Expected default 7 for nil pointer, got %v", DerefOr(nilPtr, 7))
+	}
+	if DerefOr(p, 7) != 42 {
+		t.Errorf("Expected 42, got %v", DerefOr(p, 7))
+	}
+}
+
+func TestText(t *testing.T) {
+	result := ToText("hello")
+	if !result.Valid || result.String != "hello" {
+		t.Errorf("Expected valid text 'hello', got valid=%v, string=%v", result.Valid, result.String)
+	}
+
+	if ToTextPtr(nil).Valid {
+		t.Error("Expected invalid text for nil")
+	}
+
+	str := "hello"
+	if got := ToTextPtr(&str); !got.Valid || got.String != "hello" {
+		t.Errorf("Expected valid text 'hello', got %+v", got)
+	}
+
+	if FromText(pgtype.Text{String: "stale", Valid: false}) != "" {
+		t.Error("Expected empty string for invalid text")
+	}
+
+	if ptr := TextPtr(pgtype.Text{String: "hello", Valid: true}); ptr == nil || *ptr != "hello" {
+		t.Errorf("Expected 'hello', got %v", ptr)
+	}
+	if TextPtr(pgtype.Text{}) != nil {
+		t.Error("Expected nil for invalid text")
+	}
+}
+
+func TestInts(t *testing.T) {
+	if got := ToInt4(42); !got.Valid || got.Int32 != 42 {
+		t.Errorf("Expected valid int4 42, got %+v", got)
+	}
+	if ToInt4Ptr(nil).Valid {
+		t.Error("Expected invalid int4 for nil")
+	}
+	if FromInt4(pgtype.Int4{}) != 0 {
+		t.Error("Expected 0 for invalid int4")
+	}
+	if ptr := Int4Ptr(pgtype.Int4{Int32: 7, Valid: true}); ptr == nil || *ptr != 7 {
+		t.Errorf("Expected 7, got %v", ptr)
+	}
+
+	val := int64(42)
+	if got := ToInt8Ptr(&val); !got.Valid || got.Int64 != 42 {
+		t.Errorf("Expected valid int8 42, got %+v", got)
+	}
+	if Int8Ptr(pgtype.Int8{}) != nil {
+		t.Error("Expected nil for invalid int8")
+	}
+
+	if got := FromInt2(ToInt2(3)); got != 3 {
+		t.Errorf("Expected 3, got %v", got)
+	}
+}
+
+func TestFloatsAndBool(t *testing.T) {
+	if got := FromFloat8(ToFloat8(1.5)); got != 1.5 {
+		t.Errorf("Expected 1.5, got %v", got)
+	}
+	if Float4Ptr(pgtype.Float4{}) != nil {
+		t.Error("Expected nil for invalid float4")
+	}
+
+	b := true
+	if got := ToBoolPtr(&b); !got.Valid || !got.Bool {
+		t.Errorf("Expected valid true, got %+v", got)
+	}
+	if BoolPtr(pgtype.Bool{}) != nil {
+		t.Error("Expected nil for invalid bool")
+	}
+}
+
+func TestTimes(t *testing.T) {
+	now := time.Now()
+
+	if got := FromTimestamptz(ToTimestamptz(now)); !got.Equal(now) {
+		t.Errorf("Expected %v, got %v", now, got)
+	}
+	if !FromTimestamptz(pgtype.Timestamptz{}).IsZero() {
+		t.Error("Expected zero time for invalid timestamptz")
+	}
+	if TimestamptzPtr(pgtype.Timestamptz{}) != nil {
+		t.Error("Expected nil for invalid timestamptz")
+	}
+	if ToTimestampPtr(nil).Valid {
+		t.Error("Expected invalid timestamp for nil")
+	}
+	if ptr := DatePtr(ToDate(now)); ptr == nil || !ptr.Equal(now) {
+		t.Errorf("Expected %v, got %v", now, ptr)
+	}
+}
+
+func TestUUID(t *testing.T) {
+	id := uuid.New()
+
+	pgID := ToUUID(id)
+	if !pgID.Valid {
+		t.Error("Expected valid UUID")
+	}
+	if FromUUID(pgID) != id {
+		t.Errorf("Expected %v, got %v", id, FromUUID(pgID))
+	}
+	if FromUUID(pgtype.UUID{}) != uuid.Nil {
+		t.Error("Expected uuid.Nil for invalid UUID")
+	}
+	if ptr := UUIDPtr(pgID); ptr == nil || *ptr != id {
+		t.Errorf("Expected %v, got %v", id, ptr)
+	}
+	if ToUUIDPtr(nil).Valid {
+		t.Error("Expected invalid UUID for nil")
+	}
+}
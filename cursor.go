@@ -0,0 +1,233 @@
+package dbutil
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned when a cursor is malformed, has an unknown version
+// or key type, or fails signature verification
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorVersion is the current cursor token format version
+const cursorVersion = 1
+
+// cursorSignatureSize is the number of HMAC-SHA256 bytes kept in a token
+const cursorSignatureSize = 16
+
+// CursorKey is one named ordering value embedded in a cursor
+type CursorKey struct {
+	Name  string
+	Value interface{}
+}
+
+// cursorKeyType converts values of one Go type to and from their cursor string form
+type cursorKeyType struct {
+	name   string
+	encode func(interface{}) (string, error)
+	decode func(string) (interface{}, error)
+}
+
+var (
+	cursorTypesMu     sync.RWMutex
+	cursorTypesByGo   = make(map[reflect.Type]cursorKeyType)
+	cursorTypesByName = make(map[string]cursorKeyType)
+)
+
+func init() {
+	mustRegisterCursorKeyType("s",
+		func(v string) (string, error) { return v, nil },
+		func(s string) (string, error) { return s, nil })
+	mustRegisterCursorKeyType("i",
+		func(v int) (string, error) { return strconv.Itoa(v), nil },
+		strconv.Atoi)
+	mustRegisterCursorKeyType("i32",
+		func(v int32) (string, error) { return strconv.FormatInt(int64(v), 10), nil },
+		func(s string) (int32, error) {
+			n, err := strconv.ParseInt(s, 10, 32)
+			return int32(n), err
+		})
+	mustRegisterCursorKeyType("i64",
+		func(v int64) (string, error) { return strconv.FormatInt(v, 10), nil },
+		func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) })
+	mustRegisterCursorKeyType("f64",
+		func(v float64) (string, error) { return strconv.FormatFloat(v, 'g', -1, 64), nil },
+		func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+	mustRegisterCursorKeyType("b",
+		func(v bool) (string, error) { return strconv.FormatBool(v), nil },
+		strconv.ParseBool)
+	mustRegisterCursorKeyType("t",
+		func(v time.Time) (string, error) { return v.Format(time.RFC3339Nano), nil },
+		func(s string) (time.Time, error) { return time.Parse(time.RFC3339Nano, s) })
+	mustRegisterCursorKeyType("u",
+		func(v uuid.UUID) (string, error) { return v.String(), nil },
+		uuid.Parse)
+}
+
+// RegisterCursorKeyType registers a custom Go type so it can be embedded in cursors.
+// name identifies the type inside tokens and must be stable across deployments.
+// string, int, int32, int64, float64, bool, time.Time and uuid.UUID are registered by default.
+func RegisterCursorKeyType[T any](name string, encode func(T) (string, error), decode func(string) (T, error)) error {
+	if name == "" {
+		return fmt.Errorf("cursor key type name cannot be empty")
+	}
+	if encode == nil || decode == nil {
+		return fmt.Errorf("cursor key type %s requires encode and decode functions", name)
+	}
+
+	goType := reflect.TypeOf((*T)(nil)).Elem()
+	kt := cursorKeyType{
+		name: name,
+		encode: func(v interface{}) (string, error) {
+			return encode(v.(T))
+		},
+		decode: func(s string) (interface{}, error) {
+			return decode(s)
+		},
+	}
+
+	cursorTypesMu.Lock()
+	defer cursorTypesMu.Unlock()
+
+	if _, exists := cursorTypesByName[name]; exists {
+		return fmt.Errorf("cursor key type %s is already registered", name)
+	}
+	if _, exists := cursorTypesByGo[goType]; exists {
+		return fmt.Errorf("cursor key type for %s is already registered", goType)
+	}
+	cursorTypesByName[name] = kt
+	cursorTypesByGo[goType] = kt
+	return nil
+}
+
+// mustRegisterCursorKeyType registers a built-in cursor key type
+func mustRegisterCursorKeyType[T any](name string, encode func(T) (string, error), decode func(string) (T, error)) {
+	if err := RegisterCursorKeyType(name, encode, decode); err != nil {
+		panic(err)
+	}
+}
+
+// CursorCodec encodes ordered key values as compact, versioned, HMAC-signed tokens.
+// Signing prevents clients from forging cursors that skip filters.
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec creates a codec that signs cursors with secret
+func NewCursorCodec(secret []byte) (*CursorCodec, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("cursor secret cannot be empty")
+	}
+	return &CursorCodec{secret: secret}, nil
+}
+
+// cursorPayload is the JSON body of a token: version plus [name, type, value] triples
+type cursorPayload struct {
+	V int         `json:"v"`
+	K [][3]string `json:"k"`
+}
+
+// Encode returns a token embedding keys in order
+func (c *CursorCodec) Encode(keys ...CursorKey) (string, error) {
+	payload := cursorPayload{V: cursorVersion, K: make([][3]string, 0, len(keys))}
+
+	cursorTypesMu.RLock()
+	defer cursorTypesMu.RUnlock()
+
+	for _, key := range keys {
+		kt, ok := cursorTypesByGo[reflect.TypeOf(key.Value)]
+		if !ok {
+			return "", fmt.Errorf("cursor key %s: unregistered type %T", key.Name, key.Value)
+		}
+		value, err := kt.encode(key.Value)
+		if err != nil {
+			return "", fmt.Errorf("cursor key %s: %w", key.Name, err)
+		}
+		payload.K = append(payload.K, [3]string{key.Name, kt.name, value})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(body) + "." + base64.RawURLEncoding.EncodeToString(c.sign(body)), nil
+}
+
+// Decode verifies a token and returns its keys in order.
+// All failures wrap ErrInvalidCursor.
+func (c *CursorCodec) Decode(cursor string) ([]CursorKey, error) {
+	encodedBody, encodedSig, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidCursor)
+	}
+	body, err := base64.RawURLEncoding.DecodeString(encodedBody)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if !hmac.Equal(sig, c.sign(body)) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidCursor)
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if payload.V != cursorVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidCursor, payload.V)
+	}
+
+	cursorTypesMu.RLock()
+	defer cursorTypesMu.RUnlock()
+
+	keys := make([]CursorKey, 0, len(payload.K))
+	for _, k := range payload.K {
+		kt, ok := cursorTypesByName[k[1]]
+		if !ok {
+			return nil, fmt.Errorf("%w: key %s has unknown type %s", ErrInvalidCursor, k[0], k[1])
+		}
+		value, err := kt.decode(k[2])
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %s: %v", ErrInvalidCursor, k[0], err)
+		}
+		keys = append(keys, CursorKey{Name: k[0], Value: value})
+	}
+	return keys, nil
+}
+
+// sign returns the truncated HMAC-SHA256 of body
+func (c *CursorCodec) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(body)
+	return mac.Sum(nil)[:cursorSignatureSize]
+}
+
+// CursorValue returns the value of the named key with type T
+func CursorValue[T any](keys []CursorKey, name string) (T, error) {
+	var zero T
+	for _, key := range keys {
+		if key.Name != name {
+			continue
+		}
+		v, ok := key.Value.(T)
+		if !ok {
+			return zero, fmt.Errorf("%w: key %s has type %T, expected %T", ErrInvalidCursor, name, key.Value, zero)
+		}
+		return v, nil
+	}
+	return zero, fmt.Errorf("%w: key %s not found", ErrInvalidCursor, name)
+}
@@ -0,0 +1,130 @@
+package dbutil
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type testOrderStatus string
+
+// registerTestOrderStatus registers testOrderStatus once, so tests survive -count=N
+var registerTestOrderStatus = sync.OnceValue(func() error {
+	return RegisterCursorKeyType("test_order_status",
+		func(v testOrderStatus) (string, error) { return string(v), nil },
+		func(s string) (testOrderStatus, error) { return testOrderStatus(s), nil })
+})
+
+func TestCursorCodecRoundTrip(t *testing.T) {
+	codec, err := NewCursorCodec([]byte("test-secret"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC)
+	id := uuid.New()
+
+	token, err := codec.Encode(
+		CursorKey{Name: "created_at", Value: createdAt},
+		CursorKey{Name: "id", Value: id},
+		CursorKey{Name: "score", Value: int64(42)},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	keys, err := codec.Decode(token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(keys) != 3 || keys[0].Name != "created_at" || keys[1].Name != "id" || keys[2].Name != "score" {
+		t.Fatalf("Expected keys in order, got %v", keys)
+	}
+
+	gotTime, err := CursorValue[time.Time](keys, "created_at")
+	if err != nil || !gotTime.Equal(createdAt) {
+		t.Errorf("Expected %v, got %v (err %v)", createdAt, gotTime, err)
+	}
+	gotID, err := CursorValue[uuid.UUID](keys, "id")
+	if err != nil || gotID != id {
+		t.Errorf("Expected %s, got %s (err %v)", id, gotID, err)
+	}
+	if _, err := CursorValue[string](keys, "score"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor for wrong type, got %v", err)
+	}
+	if _, err := CursorValue[string](keys, "missing"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor for missing key, got %v", err)
+	}
+
+	if _, err := NewCursorCodec(nil); err == nil {
+		t.Error("Expected error for empty secret")
+	}
+}
+
+func TestCursorCodecRejectsTampering(t *testing.T) {
+	codec, _ := NewCursorCodec([]byte("test-secret"))
+	other, _ := NewCursorCodec([]byte("other-secret"))
+
+	token, err := codec.Encode(CursorKey{Name: "id", Value: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := other.Decode(token); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor for different secret, got %v", err)
+	}
+
+	body, sig, _ := strings.Cut(token, ".")
+	forged := body[:len(body)-2] + "AA." + sig
+	if _, err := codec.Decode(forged); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor for modified body, got %v", err)
+	}
+
+	for _, bad := range []string{"", "abc", "a.b.c", "!!.!!"} {
+		if _, err := codec.Decode(bad); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor for %q, got %v", bad, err)
+		}
+	}
+
+	if _, err := codec.Encode(CursorKey{Name: "status", Value: float32(1)}); err == nil {
+		t.Error("Expected error for unregistered type")
+	}
+}
+
+func TestRegisterCursorKeyType(t *testing.T) {
+	err := registerTestOrderStatus()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	codec, _ := NewCursorCodec([]byte("test-secret"))
+	token, err := codec.Encode(CursorKey{Name: "status", Value: testOrderStatus("paid")})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	keys, err := codec.Decode(token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status, _ := CursorValue[testOrderStatus](keys, "status"); status != "paid" {
+		t.Errorf("Expected 'paid', got '%s'", status)
+	}
+
+	// Test duplicate registrations
+	err = RegisterCursorKeyType("test_order_status",
+		func(v uint8) (string, error) { return "", nil },
+		func(s string) (uint8, error) { return 0, nil })
+	if err == nil {
+		t.Error("Expected error for duplicate name")
+	}
+	err = RegisterCursorKeyType("test_order_status_2",
+		func(v testOrderStatus) (string, error) { return string(v), nil },
+		func(s string) (testOrderStatus, error) { return testOrderStatus(s), nil })
+	if err == nil {
+		t.Error("Expected error for duplicate type")
+	}
+}
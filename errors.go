@@ -1,6 +1,15 @@
 package dbutil
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrNotFound is returned (wrapped in a *NotFoundError) when a single-row lookup
+// finds no rows. Callers can compare against it with errors.Is without importing pgx.
+var ErrNotFound = errors.New("not found")
 
 // Database error types - these are generic errors that can be used by any repository.
 // These errors provide consistent error handling across database operations and can be
@@ -11,12 +20,22 @@ import "fmt"
 type NotFoundError struct {
 	Entity     string
 	Identifier interface{}
+	Err        error // original error, typically pgx.ErrNoRows (may be nil)
 }
 
 func (e *NotFoundError) Error() string {
 	return fmt.Sprintf("%s not found: %v", e.Entity, e.Identifier)
 }
 
+// Is reports whether target is ErrNotFound so that errors.Is(err, ErrNotFound) matches.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
 // ValidationError represents validation failures that occur before database operations.
 // Use this for input validation, constraint violations, or business rule failures.
 type ValidationError struct {
@@ -54,6 +73,37 @@ func (e *DatabaseError) Unwrap() error {
 	return e.Err
 }
 
+// QueryError represents a failure while executing a query or connection helper.
+// It records which query, table, and operation failed so that logs and error
+// groupings stay consistent without manual wrapping at every call site.
+type QueryError struct {
+	Query     string
+	Table     string
+	Operation string // "query", "exec", "begin transaction", "commit transaction", ...
+	Err       error
+}
+
+func (e *QueryError) Error() string {
+	msg := "failed to " + e.Operation
+	if e.Table != "" {
+		msg += " " + e.Table
+	}
+	if e.Query != "" {
+		msg += " (query " + e.Query + ")"
+	}
+	return fmt.Sprintf("%s: %v", msg, e.Err)
+}
+
+// Is reports whether target is ErrNotFound and the query found no rows, so that
+// errors.Is(err, ErrNotFound) matches a failed QueryRow scan without importing pgx.
+func (e *QueryError) Is(target error) bool {
+	return target == ErrNotFound && errors.Is(e.Err, pgx.ErrNoRows)
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
 // Error constructor functions for common cases.
 // These functions provide a consistent way to create structured database errors.
 
@@ -84,3 +134,27 @@ func NewDatabaseError(entity, operation string, err error) *DatabaseError {
 		Err:       err,
 	}
 }
+
+// NewQueryError creates a new QueryError with the given query name, table, operation, and underlying error.
+func NewQueryError(query, table, operation string, err error) *QueryError {
+	return &QueryError{
+		Query:     query,
+		Table:     table,
+		Operation: operation,
+		Err:       err,
+	}
+}
+
+// NormalizeNotFound translates pgx.ErrNoRows into a *NotFoundError for the given entity
+// and identifier, preserving the original error via wrapping. Any other error
+// (including nil) is returned unchanged.
+func NormalizeNotFound(entity string, identifier interface{}, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &NotFoundError{
+			Entity:     entity,
+			Identifier: identifier,
+			Err:        err,
+		}
+	}
+	return err
+}
@@ -2,9 +2,11 @@ package dbutil
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 func TestNewNotFoundError(t *testing.T) {
@@ -45,6 +47,54 @@ func TestNewNotFoundError(t *testing.T) {
 	}
 }
 
+func TestErrNotFound(t *testing.T) {
+	err := NewNotFoundError("User", "123")
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Expected NotFoundError to match ErrNotFound")
+	}
+
+	dbErr := NewDatabaseError("User", "query", errors.New("failed"))
+	if errors.Is(dbErr, ErrNotFound) {
+		t.Error("Expected DatabaseError to NOT match ErrNotFound")
+	}
+}
+
+func TestNormalizeNotFound(t *testing.T) {
+	// Test with pgx.ErrNoRows
+	err := NormalizeNotFound("User", "123", pgx.ErrNoRows)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Error("Expected original pgx.ErrNoRows to be preserved")
+	}
+
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("Expected *NotFoundError, got %T", err)
+	}
+	if notFoundErr.Entity != "User" || notFoundErr.Identifier != "123" {
+		t.Errorf("Expected User/123, got %s/%v", notFoundErr.Entity, notFoundErr.Identifier)
+	}
+
+	// Test with wrapped pgx.ErrNoRows
+	wrapped := fmt.Errorf("scan: %w", pgx.ErrNoRows)
+	if !errors.Is(NormalizeNotFound("User", "123", wrapped), ErrNotFound) {
+		t.Error("Expected wrapped pgx.ErrNoRows to be normalized")
+	}
+
+	// Test other errors pass through unchanged
+	otherErr := errors.New("connection failed")
+	if NormalizeNotFound("User", "123", otherErr) != otherErr {
+		t.Error("Expected non-ErrNoRows error to be returned unchanged")
+	}
+
+	// Test nil
+	if NormalizeNotFound("User", "123", nil) != nil {
+		t.Error("Expected nil error to stay nil")
+	}
+}
+
 func TestNewValidationError(t *testing.T) {
 	originalErr := errors.New("original error")
 
@@ -140,6 +190,54 @@ func TestNewDatabaseError(t *testing.T) {
 	}
 }
 
+func TestNewQueryError(t *testing.T) {
+	originalErr := errors.New("connection reset")
+
+	err := NewQueryError("GetUserByID", "users", "query", originalErr)
+
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) {
+		t.Fatalf("Expected *QueryError, got %T", err)
+	}
+
+	if queryErr.Query != "GetUserByID" {
+		t.Errorf("Expected query 'GetUserByID', got '%s'", queryErr.Query)
+	}
+
+	if queryErr.Table != "users" {
+		t.Errorf("Expected table 'users', got '%s'", queryErr.Table)
+	}
+
+	if queryErr.Operation != "query" {
+		t.Errorf("Expected operation 'query', got '%s'", queryErr.Operation)
+	}
+
+	expectedMsg := "failed to query users (query GetUserByID): connection reset"
+	if queryErr.Error() != expectedMsg {
+		t.Errorf("Expected message '%s', got '%s'", expectedMsg, queryErr.Error())
+	}
+
+	if !errors.Is(err, originalErr) {
+		t.Errorf("Expected errors.Is to find original error")
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected only no-rows errors to match ErrNotFound")
+	}
+
+	// A query that found no rows matches both ErrNotFound and pgx.ErrNoRows
+	noRows := NewQueryError("GetUserByID", "users", "query_row", pgx.ErrNoRows)
+	if !errors.Is(noRows, ErrNotFound) || !errors.Is(noRows, pgx.ErrNoRows) {
+		t.Errorf("Expected no-rows QueryError to match ErrNotFound and pgx.ErrNoRows")
+	}
+
+	// Test message without query name or table
+	queryErr = NewQueryError("", "", "begin transaction", originalErr)
+	expectedMsg = "failed to begin transaction: connection reset"
+	if queryErr.Error() != expectedMsg {
+		t.Errorf("Expected message '%s', got '%s'", expectedMsg, queryErr.Error())
+	}
+}
+
 func TestErrorTypeDetection(t *testing.T) {
 	// Test that we can distinguish between error types using errors.As
 	notFoundErr := NewNotFoundError("User", "123")
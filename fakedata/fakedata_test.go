@@ -0,0 +1,120 @@
+package fakedata
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDeterministic(t *testing.T) {
+	a := New(42)
+	b := New(42)
+
+	for i := 0; i < 100; i++ {
+		if a.Email() != b.Email() {
+			t.Fatal("Expected identical emails for identical seeds")
+		}
+		if a.UUID() != b.UUID() {
+			t.Fatal("Expected identical UUIDs for identical seeds")
+		}
+	}
+
+	if New(1).Sentence(5) == New(2).Sentence(5) {
+		t.Error("Expected different seeds to produce different values")
+	}
+}
+
+func TestColumnIndependence(t *testing.T) {
+	g := New(42)
+	email := g.Column("email").Email()
+
+	// Drawing other values first must not change the column's sequence
+	other := New(42)
+	_ = other.Name()
+	_ = other.Column("name").Name()
+	if other.Column("email").Email() != email {
+		t.Error("Expected column generator to depend only on seed and key")
+	}
+
+	if g.Column("email").Seed() == g.Column("name").Seed() {
+		t.Error("Expected different columns to have different seeds")
+	}
+}
+
+func TestValues(t *testing.T) {
+	g := New(7)
+
+	if n := g.Int(5, 10); n < 5 || n > 10 {
+		t.Errorf("Expected int in [5, 10], got %d", n)
+	}
+	if g.Int(3, 3) != 3 {
+		t.Error("Expected min when min == max")
+	}
+
+	// Full-width ranges must not overflow
+	g.Int64(math.MinInt64, math.MaxInt64)
+	g.Int(math.MinInt, math.MaxInt)
+	if n := g.Int64(-1, math.MaxInt64); n < -1 {
+		t.Errorf("Expected int64 in [-1, MaxInt64], got %d", n)
+	}
+	if n := g.Int64(math.MinInt64, 0); n > 0 {
+		t.Errorf("Expected int64 in [MinInt64, 0], got %d", n)
+	}
+
+	if s := g.String(12); len(s) != 12 {
+		t.Errorf("Expected 12 chars, got %q", s)
+	}
+	if s := g.String(-1); s != "" {
+		t.Errorf("Expected empty string for negative length, got %q", s)
+	}
+
+	if email := g.Email(); !strings.Contains(email, "@") {
+		t.Errorf("Expected email address, got %q", email)
+	}
+
+	if name := g.Name(); len(strings.Fields(name)) != 2 {
+		t.Errorf("Expected first and last name, got %q", name)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	if ts := g.Time(start, end); ts.Before(start) || !ts.Before(end) {
+		t.Errorf("Expected time in range, got %v", ts)
+	}
+
+	id := g.UUID()
+	if id.Version() != 4 || id.Variant() != uuid.RFC4122 {
+		t.Errorf("Expected RFC 4122 v4 UUID, got %v", id)
+	}
+}
+
+func TestValueForType(t *testing.T) {
+	g := New(1)
+
+	cases := map[string]interface{}{
+		"text":        "",
+		"int4":        int32(0),
+		"int8":        int64(0),
+		"bool":        false,
+		"uuid":        uuid.UUID{},
+		"timestamptz": time.Time{},
+	}
+	for pgType, want := range cases {
+		got, err := g.Value(pgType)
+		if err != nil {
+			t.Errorf("Expected no error for %s, got %v", pgType, err)
+			continue
+		}
+		if gotType, wantType := fmt.Sprintf("%T", got), fmt.Sprintf("%T", want); gotType != wantType {
+			t.Errorf("Expected %s for %s, got %s", wantType, pgType, gotType)
+		}
+	}
+
+	if _, err := g.Value("tsvector"); err == nil {
+		t.Error("Expected error for unsupported type")
+	}
+}
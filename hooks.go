@@ -3,10 +3,20 @@ package dbutil
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
 
+// QueryEvent describes a statement executed through Connection.Exec, Query or QueryRow
+type QueryEvent struct {
+	Name      string // query name from WithQueryName, or the operation ("exec", "query", "query_row")
+	Operation string
+	SQL       string
+	Duration  time.Duration
+	Err       error
+}
+
 // ConnectionHooks manages connection lifecycle hooks
 type ConnectionHooks struct {
 	mu           sync.RWMutex
@@ -14,6 +24,7 @@ type ConnectionHooks struct {
 	onDisconnect []func(*pgx.Conn)
 	onAcquire    []func(context.Context, *pgx.Conn) error
 	onRelease    []func(*pgx.Conn)
+	onQuery      []func(context.Context, QueryEvent)
 }
 
 // NewConnectionHooks creates a new connection hooks manager
@@ -23,6 +34,7 @@ func NewConnectionHooks() *ConnectionHooks {
 		onDisconnect: make([]func(*pgx.Conn), 0),
 		onAcquire:    make([]func(context.Context, *pgx.Conn) error, 0),
 		onRelease:    make([]func(*pgx.Conn), 0),
+		onQuery:      make([]func(context.Context, QueryEvent), 0),
 	}
 }
 
@@ -54,6 +66,14 @@ func (h *ConnectionHooks) AddOnRelease(fn func(*pgx.Conn)) {
 	h.onRelease = append(h.onRelease, fn)
 }
 
+// AddOnQuery adds a callback that will be called after a statement is executed
+// through Connection.Exec, Query or QueryRow
+func (h *ConnectionHooks) AddOnQuery(fn func(context.Context, QueryEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onQuery = append(h.onQuery, fn)
+}
+
 // ExecuteOnConnect executes all OnConnect callbacks
 func (h *ConnectionHooks) ExecuteOnConnect(conn *pgx.Conn) error {
 	h.mu.RLock()
@@ -100,6 +120,16 @@ func (h *ConnectionHooks) ExecuteOnRelease(conn *pgx.Conn) {
 	}
 }
 
+// ExecuteOnQuery executes all OnQuery callbacks
+func (h *ConnectionHooks) ExecuteOnQuery(ctx context.Context, event QueryEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, fn := range h.onQuery {
+		fn(ctx, event)
+	}
+}
+
 // Common hook functions for typical use cases
 
 // LoggingHook creates a hook that logs connection events
@@ -213,6 +243,10 @@ func CombineHooks(hooksList ...*ConnectionHooks) *ConnectionHooks {
 			combined.AddOnRelease(fn)
 		}
 
+		for _, fn := range hooks.onQuery {
+			combined.AddOnQuery(fn)
+		}
+
 		hooks.mu.RUnlock()
 	}
 
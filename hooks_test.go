@@ -285,3 +285,19 @@ func (m *mockLogger) Log(ctx context.Context, level LogLevel, msg string, data m
 		m.logFunc(ctx, level, msg, data)
 	}
 }
+
+func TestAddOnQueryHooks(t *testing.T) {
+	hooks := NewConnectionHooks()
+	var names []string
+
+	hooks.AddOnQuery(func(ctx context.Context, event QueryEvent) {
+		names = append(names, event.Name)
+	})
+
+	combined := CombineHooks(hooks, NewConnectionHooks())
+	combined.ExecuteOnQuery(context.Background(), QueryEvent{Name: "GetUser"})
+
+	if len(names) != 1 || names[0] != "GetUser" {
+		t.Errorf("Expected OnQuery hook to receive 'GetUser', got %v", names)
+	}
+}
@@ -0,0 +1,45 @@
+package dbutil
+
+import (
+	"github.com/google/uuid"
+)
+
+// IDGenerator produces primary keys for rows created client-side
+type IDGenerator interface {
+	NewID() (uuid.UUID, error)
+}
+
+// IDGeneratorFunc adapts a function to the IDGenerator interface
+type IDGeneratorFunc func() (uuid.UUID, error)
+
+// NewID calls f()
+func (f IDGeneratorFunc) NewID() (uuid.UUID, error) {
+	return f()
+}
+
+// UUIDv7Generator generates time-ordered UUID v7 IDs, which keep B-tree indexes compact
+// and work with ID-based cursor pagination
+type UUIDv7Generator struct{}
+
+// NewID returns a new UUID v7
+func (UUIDv7Generator) NewID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+// UUIDv4Generator generates random UUID v4 IDs
+type UUIDv4Generator struct{}
+
+// NewID returns a new UUID v4
+func (UUIDv4Generator) NewID() (uuid.UUID, error) {
+	return uuid.NewRandom()
+}
+
+// NewUUID returns a new UUID v7. It panics if the system random source is unavailable.
+// Code that needs deterministic IDs in tests should accept an IDGenerator instead.
+func NewUUID() uuid.UUID {
+	id, err := UUIDv7Generator{}.NewID()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
@@ -0,0 +1,34 @@
+package dbutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewUUID(t *testing.T) {
+	first := NewUUID()
+	if first.Version() != 7 {
+		t.Errorf("Expected UUID v7, got v%d", first.Version())
+	}
+
+	// UUID v7 values are time-ordered
+	second := NewUUID()
+	if bytes.Compare(first[:], second[:]) >= 0 {
+		t.Errorf("Expected %s to sort before %s", first, second)
+	}
+}
+
+func TestIDGenerators(t *testing.T) {
+	id, err := UUIDv4Generator{}.NewID()
+	if err != nil || id.Version() != 4 {
+		t.Errorf("Expected UUID v4, got %s (err %v)", id, err)
+	}
+
+	fixed := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	var gen IDGenerator = IDGeneratorFunc(func() (uuid.UUID, error) { return fixed, nil })
+	if id, err := gen.NewID(); err != nil || id != fixed {
+		t.Errorf("Expected %s, got %s (err %v)", fixed, id, err)
+	}
+}
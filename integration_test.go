@@ -2,6 +2,8 @@ package dbutil
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/jackc/pgx/v5"
@@ -85,6 +87,70 @@ func TestCleanupTestData(t *testing.T) {
 	CleanupTestData((*Connection[*MockQuerier])(nil), "SELECT 1")
 }
 
+func TestTruncateTablesSQL(t *testing.T) {
+	sql := truncateTablesSQL([]string{"users", "billing.invoices"})
+	expected := `TRUNCATE TABLE "users", "billing"."invoices" RESTART IDENTITY CASCADE`
+	if sql != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, sql)
+	}
+}
+
+func TestExcludeTables(t *testing.T) {
+	result := excludeTables([]string{"posts", "schema_migrations", "users"}, []string{"schema_migrations"})
+	if len(result) != 2 || result[0] != "posts" || result[1] != "users" {
+		t.Errorf("Expected [posts users], got %v", result)
+	}
+}
+
+func TestTruncateTables(t *testing.T) {
+	conn := GetTestConnection(NewMockQuerier)
+	if conn == nil {
+		t.Skip("TEST_DATABASE_URL not set, skipping integration test")
+		return
+	}
+
+	ctx := context.Background()
+	_, err := conn.GetDB().Exec(ctx, "CREATE TABLE IF NOT EXISTS dbutil_truncate_test (id SERIAL PRIMARY KEY, name TEXT)")
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	defer func() {
+		_, _ = conn.GetDB().Exec(ctx, "DROP TABLE IF EXISTS dbutil_truncate_test")
+	}()
+
+	if err := TruncateTables(conn, "dbutil_truncate_test"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// Test with a table that does not exist
+	if err := TruncateTables(conn, "dbutil_missing_table"); err == nil {
+		t.Error("Expected error for missing table")
+	}
+
+	// Test with nil connection (should not panic)
+	if err := TruncateTables((*Connection[*MockQuerier])(nil), "users"); err != nil {
+		t.Errorf("Expected no error for nil connection, got %v", err)
+	}
+
+	// Test that the context is honored
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := TruncateTablesContext(cancelled, conn, "dbutil_truncate_test"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestNewTestSchemaName(t *testing.T) {
+	name := newTestSchemaName()
+	if !strings.HasPrefix(name, "dbutil_test_") || len(name) != len("dbutil_test_")+12 {
+		t.Errorf("Expected name like 'dbutil_test_<12 hex chars>', got '%s'", name)
+	}
+
+	if newTestSchemaName() == name {
+		t.Error("Expected unique schema names")
+	}
+}
+
 func TestConnectionHealthCheck(t *testing.T) {
 	conn := GetTestConnection(NewMockQuerier)
 	if conn == nil {
@@ -262,3 +328,32 @@ func TestNewConnectionWithValidationHooks(t *testing.T) {
 		}
 	}
 }
+
+func TestConnectionInterfaceAccessors(t *testing.T) {
+	conn := GetTestConnection(NewMockQuerier)
+	if conn == nil {
+		t.Skip("TEST_DATABASE_URL not set, skipping integration test")
+		return
+	}
+
+	ctx := context.Background()
+
+	if _, err := conn.Execer().Exec(ctx, "SELECT 1"); err != nil {
+		t.Errorf("Expected no error from Execer, got %v", err)
+	}
+
+	var n int
+	if err := conn.Queryer().QueryRow(ctx, "SELECT 1").Scan(&n); err != nil || n != 1 {
+		t.Errorf("Expected 1 from Queryer, got %d (err %v)", n, err)
+	}
+
+	tx, err := conn.Beginner().Begin(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error from Beginner, got %v", err)
+	}
+	_ = tx.Rollback(ctx)
+
+	if conn.DBTX() == nil {
+		t.Error("Expected DBTX to return non-nil")
+	}
+}
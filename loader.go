@@ -0,0 +1,190 @@
+package dbutil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Default batching settings for Loader
+const (
+	DefaultLoaderMaxBatch = 100
+	DefaultLoaderWait     = 2 * time.Millisecond
+	DefaultLoaderTimeout  = 5 * time.Second
+)
+
+// BatchFunc fetches the values for a batch of keys, typically with a single
+// `WHERE id = ANY($1)` query. Keys missing from the returned map are reported as not found.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// LoaderOptions configures how a Loader groups keys into batches
+type LoaderOptions struct {
+	// MaxBatch is the maximum number of keys per batch (default DefaultLoaderMaxBatch)
+	MaxBatch int
+	// Wait is how long the first Load in a batch waits for more keys (default DefaultLoaderWait)
+	Wait time.Duration
+	// Entity names the loaded type in NotFoundError messages (default "Record")
+	Entity string
+	// Timeout bounds each batch fetch (default DefaultLoaderTimeout)
+	Timeout time.Duration
+}
+
+// Loader coalesces concurrent Load calls into batched fetches so that N lookups
+// by ID become a single query. Results are not cached between batches.
+//
+// A Loader belongs to one request: batches are fetched with the context passed to
+// NewLoader, so tenant, actor and tracing values come from that request rather than
+// from whichever caller happened to start the batch. Create a new Loader per request.
+type Loader[K comparable, V any] struct {
+	ctx      context.Context
+	fetch    BatchFunc[K, V]
+	maxBatch int
+	wait     time.Duration
+	timeout  time.Duration
+	entity   string
+
+	mu    sync.Mutex
+	batch *loaderBatch[K, V]
+}
+
+// loaderBatch is a set of keys fetched together
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	seen    map[K]struct{}
+	once    sync.Once
+	done    chan struct{}
+	results map[K]V
+	err     error
+}
+
+// NewLoader creates a Loader for the request ctx that fetches batches with fetch.
+// Each fetch runs with ctx and the configured timeout.
+func NewLoader[K comparable, V any](ctx context.Context, fetch BatchFunc[K, V], opts *LoaderOptions) (*Loader[K, V], error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	if fetch == nil {
+		return nil, fmt.Errorf("batch function cannot be nil")
+	}
+
+	l := &Loader[K, V]{
+		ctx:      ctx,
+		fetch:    fetch,
+		maxBatch: DefaultLoaderMaxBatch,
+		wait:     DefaultLoaderWait,
+		timeout:  DefaultLoaderTimeout,
+		entity:   "Record",
+	}
+	if opts != nil {
+		if opts.MaxBatch > 0 {
+			l.maxBatch = opts.MaxBatch
+		}
+		if opts.Wait > 0 {
+			l.wait = opts.Wait
+		}
+		if opts.Entity != "" {
+			l.entity = opts.Entity
+		}
+		if opts.Timeout > 0 {
+			l.timeout = opts.Timeout
+		}
+	}
+	return l, nil
+}
+
+// Load returns the value for key, batching the lookup with other concurrent calls.
+// ctx only bounds how long the caller waits; the fetch itself uses the loader's context.
+// It returns a NotFoundError if the batch function did not return the key.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	var zero V
+	if ctx == nil {
+		return zero, fmt.Errorf("context cannot be nil")
+	}
+	return l.await(ctx, l.enqueue(key), key)
+}
+
+// LoadMany returns the values for keys in order, batching them with other concurrent calls.
+// It returns the first error encountered.
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	batches := make([]*loaderBatch[K, V], len(keys))
+	for i, key := range keys {
+		batches[i] = l.enqueue(key)
+	}
+
+	values := make([]V, len(keys))
+	for i, key := range keys {
+		v, err := l.await(ctx, batches[i], key)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// enqueue adds key to the pending batch, starting a new batch if needed
+func (l *Loader[K, V]) enqueue(key K) *loaderBatch[K, V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.batch
+	if b == nil {
+		b = &loaderBatch[K, V]{
+			seen: make(map[K]struct{}),
+			done: make(chan struct{}),
+		}
+		l.batch = b
+		time.AfterFunc(l.wait, func() { l.dispatch(b) })
+	}
+
+	if _, ok := b.seen[key]; !ok {
+		b.seen[key] = struct{}{}
+		b.keys = append(b.keys, key)
+	}
+
+	if len(b.keys) >= l.maxBatch {
+		l.batch = nil
+		go l.dispatch(b)
+	}
+	return b
+}
+
+// dispatch fetches a batch exactly once
+func (l *Loader[K, V]) dispatch(b *loaderBatch[K, V]) {
+	l.mu.Lock()
+	if l.batch == b {
+		l.batch = nil
+	}
+	l.mu.Unlock()
+
+	b.once.Do(func() {
+		ctx, cancel := context.WithTimeout(l.ctx, l.timeout)
+		defer cancel()
+		b.results, b.err = l.fetch(ctx, b.keys)
+		close(b.done)
+	})
+}
+
+// await waits for the batch holding key to complete
+func (l *Loader[K, V]) await(ctx context.Context, b *loaderBatch[K, V], key K) (V, error) {
+	var zero V
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+
+	if b.err != nil {
+		return zero, b.err
+	}
+	v, ok := b.results[key]
+	if !ok {
+		return zero, NewNotFoundError(l.entity, key)
+	}
+	return v, nil
+}
@@ -0,0 +1,169 @@
+package dbutil
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoaderBatchesConcurrentLoads(t *testing.T) {
+	var calls int32
+	var batchSizes []int
+	var mu sync.Mutex
+
+	loader, err := NewLoader(context.Background(), func(ctx context.Context, keys []int) (map[int]string, error) {
+		atomic.AddInt32(&calls, 1)
+		mu.Lock()
+		batchSizes = append(batchSizes, len(keys))
+		mu.Unlock()
+
+		results := make(map[int]string, len(keys))
+		for _, k := range keys {
+			results[k] = "user-" + strconv.Itoa(k)
+		}
+		return results, nil
+	}, &LoaderOptions{Wait: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			// Duplicate keys should be coalesced within a batch
+			v, err := loader.Load(context.Background(), id%5)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+				return
+			}
+			if v != "user-"+strconv.Itoa(id%5) {
+				t.Errorf("Expected 'user-%d', got '%s'", id%5, v)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected 1 batch call, got %d", calls)
+	}
+	if len(batchSizes) != 1 || batchSizes[0] != 5 {
+		t.Errorf("Expected a single batch of 5 unique keys, got %v", batchSizes)
+	}
+}
+
+func TestLoaderMaxBatch(t *testing.T) {
+	var calls int32
+	loader, err := NewLoader(context.Background(), func(ctx context.Context, keys []int) (map[int]int, error) {
+		atomic.AddInt32(&calls, 1)
+		if len(keys) > 2 {
+			t.Errorf("Expected at most 2 keys per batch, got %d", len(keys))
+		}
+		results := make(map[int]int, len(keys))
+		for _, k := range keys {
+			results[k] = k * 10
+		}
+		return results, nil
+	}, &LoaderOptions{MaxBatch: 2, Wait: time.Second})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	values, err := loader.LoadMany(context.Background(), []int{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for i, v := range values {
+		if v != (i+1)*10 {
+			t.Errorf("Expected %d, got %d", (i+1)*10, v)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 batch calls, got %d", calls)
+	}
+}
+
+func TestLoaderNotFoundAndErrors(t *testing.T) {
+	loader, err := NewLoader(context.Background(), func(ctx context.Context, keys []string) (map[string]int, error) {
+		return map[string]int{"a": 1}, nil
+	}, &LoaderOptions{Entity: "User"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := loader.Load(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	fetchErr := errors.New("connection refused")
+	failing, err := NewLoader(context.Background(), func(ctx context.Context, keys []string) (map[string]int, error) {
+		return nil, fetchErr
+	}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := failing.Load(context.Background(), "a"); !errors.Is(err, fetchErr) {
+		t.Errorf("Expected fetch error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	slow, err := NewLoader(context.Background(), func(ctx context.Context, keys []string) (map[string]int, error) {
+		return map[string]int{"a": 1}, nil
+	}, &LoaderOptions{Wait: time.Second})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := slow.Load(ctx, "a"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestLoaderUsesRequestContext(t *testing.T) {
+	type requestKey struct{}
+	reqCtx := context.WithValue(context.Background(), requestKey{}, "tenant-a")
+
+	loader, err := NewLoader(reqCtx, func(ctx context.Context, keys []int) (map[int]string, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("Expected the fetch context to have a deadline")
+		}
+		tenant, _ := ctx.Value(requestKey{}).(string)
+		results := make(map[int]string, len(keys))
+		for _, k := range keys {
+			results[k] = tenant
+		}
+		return results, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The fetch runs with the loader's context, not the caller's
+	v, err := loader.Load(context.WithValue(context.Background(), requestKey{}, "tenant-b"), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if v != "tenant-a" {
+		t.Errorf("Expected 'tenant-a', got '%s'", v)
+	}
+}
+
+func TestLoaderTimeout(t *testing.T) {
+	loader, err := NewLoader(context.Background(), func(ctx context.Context, keys []int) (map[int]int, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, &LoaderOptions{Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := loader.Load(context.Background(), 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -143,6 +143,11 @@ func NewConnectionWithConfigAndLogging[T Querier](ctx context.Context, dsn strin
 			return nil, err
 		}
 
+		// SearchPath also applies when an explicit DSN is given
+		if cfg != nil && cfg.SearchPath != "" {
+			config.ConnConfig.RuntimeParams["search_path"] = cfg.SearchPath
+		}
+
 		// Apply config settings
 		config.MaxConns = 10
 		config.MinConns = 1
@@ -158,6 +163,7 @@ func NewConnectionWithConfigAndLogging[T Querier](ctx context.Context, dsn strin
 			if cfg.MaxConnLifetime > 0 {
 				config.MaxConnLifetime = cfg.MaxConnLifetime
 			}
+			applyStatementCacheConfig(config.ConnConfig, cfg)
 			if cfg.OnConnect != nil {
 				config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
 					return cfg.OnConnect(conn)
@@ -182,10 +188,17 @@ func NewConnectionWithConfigAndLogging[T Querier](ctx context.Context, dsn strin
 		}
 	}
 
+	var allowList *StatementAllowList
+	if cfg != nil {
+		allowList = cfg.AllowList
+	}
+
 	conn := &Connection[T]{
-		pool:    pool,
-		queries: newQueriesFunc(pool),
-		metrics: nil,
+		pool:      pool,
+		queries:   newQueriesFunc(pool),
+		metrics:   nil,
+		server:    &serverInfo{},
+		allowList: allowList,
 	}
 
 	return &LoggingConnection[T]{
@@ -0,0 +1,313 @@
+package dbutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultOutboxTable is the outbox table used when no table name is configured
+const DefaultOutboxTable = "outbox"
+
+// Default polling settings for OutboxRelay
+const (
+	DefaultOutboxBatchSize    = 100
+	DefaultOutboxPollInterval = time.Second
+	DefaultOutboxMaxAttempts  = 5
+)
+
+// OutboxMessage is a message stored in the outbox table
+type OutboxMessage struct {
+	ID        int64
+	Topic     string
+	Payload   []byte
+	CreatedAt time.Time
+	Attempts  int // failed publish attempts so far
+}
+
+// Publisher delivers outbox messages to a message broker
+type Publisher interface {
+	Publish(ctx context.Context, msg OutboxMessage) error
+}
+
+// PublisherFunc adapts a function to the Publisher interface
+type PublisherFunc func(ctx context.Context, msg OutboxMessage) error
+
+// Publish calls f(ctx, msg)
+func (f PublisherFunc) Publish(ctx context.Context, msg OutboxMessage) error {
+	return f(ctx, msg)
+}
+
+// OutboxCreateTableSQL returns DDL creating the outbox table used by EnqueueOutbox and OutboxRelay,
+// plus a partial index over unsent messages so polling does not scan sent rows. It is safe
+// to run again and adds the attempts and last_error columns to tables created by older versions.
+// The table name may be schema-qualified (e.g. "events.outbox").
+func OutboxCreateTableSQL(table string) string {
+	parts := strings.Split(outboxTableName(table), ".")
+	index := pgx.Identifier{parts[len(parts)-1] + "_unsent_idx"}.Sanitize()
+	quoted := quoteOutboxTable(table)
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	id BIGSERIAL PRIMARY KEY,
+	topic TEXT NOT NULL,
+	payload JSONB NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	sent_at TIMESTAMPTZ,
+	attempts INT NOT NULL DEFAULT 0,
+	last_error TEXT
+);
+ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS last_error TEXT;
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (id) WHERE sent_at IS NULL`, quoted, index)
+}
+
+// EnqueueOutbox inserts a message into the outbox table. Pass the transaction that
+// performs the business change so the message is only published if it commits.
+// payload is marshalled to JSON.
+func EnqueueOutbox(ctx context.Context, db Execer, table, topic string, payload interface{}) error {
+	if topic == "" {
+		return NewValidationError("outbox message", "enqueue", "topic", "is required", nil)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return NewValidationError("outbox message", "enqueue", "payload", "cannot be marshalled to JSON", err)
+	}
+
+	if _, err := db.Exec(ctx, outboxInsertSQL(table), topic, body); err != nil {
+		return NewQueryError("", outboxTableName(table), "enqueue outbox message", err)
+	}
+	return nil
+}
+
+// OutboxRelayConfig configures an OutboxRelay
+type OutboxRelayConfig struct {
+	Table        string           // outbox table (default DefaultOutboxTable)
+	BatchSize    int              // messages claimed per poll (default DefaultOutboxBatchSize)
+	PollInterval time.Duration    // delay between polls when the outbox is drained (default DefaultOutboxPollInterval)
+	MaxAttempts  int              // failed publishes before a message is skipped (default DefaultOutboxMaxAttempts)
+	Retention    time.Duration    // if set, Run deletes messages sent longer ago than this, once per Retention
+	Metrics      MetricsCollector // records each poll as a query named "outbox_relay"
+	Logger       Logger           // logs poll and prune failures in Run
+}
+
+// OutboxRelayStats holds counters for an OutboxRelay
+type OutboxRelayStats struct {
+	Polls     int64
+	Published int64
+	Failed    int64
+}
+
+// OutboxRelay polls the outbox table and publishes unsent messages in ID order.
+// Rows are claimed with FOR UPDATE SKIP LOCKED, so several relays can run concurrently.
+//
+// A failed publish increments the message's attempts and stores last_error. Once a
+// message has failed MaxAttempts times it is no longer claimed, so it cannot block the
+// messages behind it; it stays in the table with sent_at NULL as a dead letter for
+// inspection or manual requeue (reset attempts to 0).
+type OutboxRelay struct {
+	db        Beginner
+	publisher Publisher
+	cfg       OutboxRelayConfig
+
+	claimSQL string
+	markSQL  string
+	failSQL  string
+	pruneSQL string
+
+	polls     atomic.Int64
+	published atomic.Int64
+	failed    atomic.Int64
+}
+
+// NewOutboxRelay creates a relay reading from db and publishing with publisher
+func NewOutboxRelay(db Beginner, publisher Publisher, cfg *OutboxRelayConfig) (*OutboxRelay, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database cannot be nil")
+	}
+	if publisher == nil {
+		return nil, fmt.Errorf("publisher cannot be nil")
+	}
+
+	r := &OutboxRelay{db: db, publisher: publisher}
+	if cfg != nil {
+		r.cfg = *cfg
+	}
+	r.cfg.Table = outboxTableName(r.cfg.Table)
+	if r.cfg.BatchSize <= 0 {
+		r.cfg.BatchSize = DefaultOutboxBatchSize
+	}
+	if r.cfg.PollInterval <= 0 {
+		r.cfg.PollInterval = DefaultOutboxPollInterval
+	}
+	if r.cfg.MaxAttempts <= 0 {
+		r.cfg.MaxAttempts = DefaultOutboxMaxAttempts
+	}
+
+	table := quoteOutboxTable(r.cfg.Table)
+	r.claimSQL = fmt.Sprintf("SELECT id, topic, payload, created_at, attempts FROM %s WHERE sent_at IS NULL AND attempts < $2 ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED", table)
+	r.markSQL = fmt.Sprintf("UPDATE %s SET sent_at = now() WHERE id = ANY($1)", table)
+	r.failSQL = fmt.Sprintf("UPDATE %s SET attempts = attempts + 1, last_error = $2 WHERE id = $1", table)
+	r.pruneSQL = fmt.Sprintf("DELETE FROM %s WHERE sent_at < now() - make_interval(secs => $1)", table)
+	return r, nil
+}
+
+// Run relays messages until ctx is cancelled. When a poll returns a full batch the
+// next poll starts immediately; otherwise Run waits PollInterval. If Retention is set,
+// sent messages older than Retention are pruned once per Retention.
+func (r *OutboxRelay) Run(ctx context.Context) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+
+	lastPrune := time.Now()
+	for {
+		n, err := r.RelayBatch(ctx)
+		if err != nil && ctx.Err() == nil && r.cfg.Logger != nil {
+			r.cfg.Logger.Log(ctx, LogLevelError, "Outbox relay failed", map[string]interface{}{
+				"table": r.cfg.Table,
+				"error": err.Error(),
+			})
+		}
+
+		if r.cfg.Retention > 0 && time.Since(lastPrune) >= r.cfg.Retention {
+			lastPrune = time.Now()
+			if _, err := r.PruneSent(ctx, r.cfg.Retention); err != nil && ctx.Err() == nil && r.cfg.Logger != nil {
+				r.cfg.Logger.Log(ctx, LogLevelError, "Outbox prune failed", map[string]interface{}{
+					"table": r.cfg.Table,
+					"error": err.Error(),
+				})
+			}
+		}
+		if err == nil && n == r.cfg.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.cfg.PollInterval):
+		}
+	}
+}
+
+// RelayBatch claims up to BatchSize unsent messages, publishes them in order and marks
+// the published ones as sent. Publishing stops at the first failure, which is recorded
+// against the failed message; it and the messages after it are retried on the next poll
+// until the failed message reaches MaxAttempts. It returns the number of messages published.
+func (r *OutboxRelay) RelayBatch(ctx context.Context) (int, error) {
+	start := time.Now()
+	n, err := r.relayBatch(ctx)
+	r.polls.Add(1)
+	if r.cfg.Metrics != nil {
+		r.cfg.Metrics.RecordQueryExecuted("outbox_relay", time.Since(start), err)
+	}
+	return n, err
+}
+
+func (r *OutboxRelay) relayBatch(ctx context.Context) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, NewQueryError("", "", "begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	rows, err := tx.Query(ctx, r.claimSQL, r.cfg.BatchSize, r.cfg.MaxAttempts)
+	if err != nil {
+		return 0, NewQueryError("", r.cfg.Table, "claim outbox messages", err)
+	}
+	msgs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (OutboxMessage, error) {
+		var msg OutboxMessage
+		err := row.Scan(&msg.ID, &msg.Topic, &msg.Payload, &msg.CreatedAt, &msg.Attempts)
+		return msg, err
+	})
+	if err != nil {
+		return 0, NewQueryError("", r.cfg.Table, "claim outbox messages", err)
+	}
+	if len(msgs) == 0 {
+		return 0, nil
+	}
+
+	sent, publishErr := r.publish(ctx, msgs)
+	if len(sent) > 0 {
+		if _, err := tx.Exec(ctx, r.markSQL, sent); err != nil {
+			return 0, NewQueryError("", r.cfg.Table, "mark outbox messages sent", err)
+		}
+	}
+	if publishErr != nil {
+		failed := msgs[len(sent)]
+		if _, err := tx.Exec(ctx, r.failSQL, failed.ID, publishErr.Error()); err != nil {
+			return 0, NewQueryError("", r.cfg.Table, "record outbox publish failure", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, NewQueryError("", "", "commit transaction", err)
+	}
+	return len(sent), publishErr
+}
+
+// PruneSent deletes messages that were sent more than olderThan ago and returns how many were deleted
+func (r *OutboxRelay) PruneSent(ctx context.Context, olderThan time.Duration) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, NewQueryError("", "", "begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	result, err := tx.Exec(ctx, r.pruneSQL, olderThan.Seconds())
+	if err != nil {
+		return 0, NewQueryError("", r.cfg.Table, "prune outbox messages", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, NewQueryError("", "", "commit transaction", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// publish publishes msgs in order, stopping at the first failure, and returns the IDs published
+func (r *OutboxRelay) publish(ctx context.Context, msgs []OutboxMessage) ([]int64, error) {
+	sent := make([]int64, 0, len(msgs))
+	for _, msg := range msgs {
+		if err := r.publisher.Publish(ctx, msg); err != nil {
+			r.failed.Add(1)
+			return sent, fmt.Errorf("failed to publish outbox message %d: %w", msg.ID, err)
+		}
+		r.published.Add(1)
+		sent = append(sent, msg.ID)
+	}
+	return sent, nil
+}
+
+// Stats returns the relay counters
+func (r *OutboxRelay) Stats() OutboxRelayStats {
+	return OutboxRelayStats{
+		Polls:     r.polls.Load(),
+		Published: r.published.Load(),
+		Failed:    r.failed.Load(),
+	}
+}
+
+// outboxTableName returns table, or DefaultOutboxTable if it is empty
+func outboxTableName(table string) string {
+	if table == "" {
+		return DefaultOutboxTable
+	}
+	return table
+}
+
+// outboxInsertSQL returns the statement inserting one message into the outbox table
+func outboxInsertSQL(table string) string {
+	return fmt.Sprintf("INSERT INTO %s (topic, payload) VALUES ($1, $2)", quoteOutboxTable(table))
+}
+
+// quoteOutboxTable returns the outbox table quoted as an identifier, honoring schema qualification
+func quoteOutboxTable(table string) string {
+	return pgx.Identifier(strings.Split(outboxTableName(table), ".")).Sanitize()
+}
@@ -0,0 +1,220 @@
+package dbutil
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestOutboxCreateTableSQL(t *testing.T) {
+	sql := OutboxCreateTableSQL("")
+	if !strings.Contains(sql, `CREATE TABLE IF NOT EXISTS "outbox"`) {
+		t.Errorf("Expected default outbox table, got %s", sql)
+	}
+	if !strings.Contains(sql, `CREATE INDEX IF NOT EXISTS "outbox_unsent_idx" ON "outbox" (id) WHERE sent_at IS NULL`) {
+		t.Errorf("Expected partial index on unsent messages, got %s", sql)
+	}
+	if !strings.Contains(sql, "ADD COLUMN IF NOT EXISTS attempts") {
+		t.Errorf("Expected attempts column to be added to existing tables, got %s", sql)
+	}
+
+	sql = OutboxCreateTableSQL("events.outbox")
+	if !strings.Contains(sql, `"events"."outbox"`) || !strings.Contains(sql, `"outbox_unsent_idx" ON "events"."outbox"`) {
+		t.Errorf("Expected schema-qualified table, got %s", sql)
+	}
+}
+
+func TestEnqueueOutbox(t *testing.T) {
+	db := &mockDBTX{}
+	ctx := context.Background()
+
+	if err := EnqueueOutbox(ctx, db, "", "user.created", map[string]string{"id": "1"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(db.executed) != 1 || !strings.HasPrefix(db.executed[0], `INSERT INTO "outbox"`) {
+		t.Errorf("Expected insert into outbox, got %v", db.executed)
+	}
+
+	var validationErr *ValidationError
+	if err := EnqueueOutbox(ctx, db, "", "", nil); !errors.As(err, &validationErr) {
+		t.Errorf("Expected *ValidationError for empty topic, got %v", err)
+	}
+	if err := EnqueueOutbox(ctx, db, "", "user.created", make(chan int)); !errors.As(err, &validationErr) {
+		t.Errorf("Expected *ValidationError for unmarshallable payload, got %v", err)
+	}
+}
+
+func TestNewOutboxRelay(t *testing.T) {
+	publisher := PublisherFunc(func(ctx context.Context, msg OutboxMessage) error { return nil })
+
+	relay, err := NewOutboxRelay(&mockBeginner{}, publisher, &OutboxRelayConfig{Table: "events.outbox"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if relay.cfg.BatchSize != DefaultOutboxBatchSize || relay.cfg.PollInterval != DefaultOutboxPollInterval {
+		t.Errorf("Expected default batch size and poll interval, got %d and %v", relay.cfg.BatchSize, relay.cfg.PollInterval)
+	}
+	if relay.cfg.MaxAttempts != DefaultOutboxMaxAttempts {
+		t.Errorf("Expected default max attempts %d, got %d", DefaultOutboxMaxAttempts, relay.cfg.MaxAttempts)
+	}
+	if !strings.Contains(relay.claimSQL, `FROM "events"."outbox"`) || !strings.Contains(relay.claimSQL, "FOR UPDATE SKIP LOCKED") {
+		t.Errorf("Unexpected claim SQL: %s", relay.claimSQL)
+	}
+	if !strings.Contains(relay.claimSQL, "attempts < $2") {
+		t.Errorf("Expected claim SQL to skip dead letters, got %s", relay.claimSQL)
+	}
+
+	if _, err := NewOutboxRelay(nil, publisher, nil); err == nil {
+		t.Error("Expected error for nil database")
+	}
+	if _, err := NewOutboxRelay(&mockBeginner{}, nil, nil); err == nil {
+		t.Error("Expected error for nil publisher")
+	}
+}
+
+func TestOutboxRelayPublishStopsAtFailure(t *testing.T) {
+	publishErr := errors.New("broker unavailable")
+	var published []int64
+	publisher := PublisherFunc(func(ctx context.Context, msg OutboxMessage) error {
+		if msg.ID == 3 {
+			return publishErr
+		}
+		published = append(published, msg.ID)
+		return nil
+	})
+
+	relay, _ := NewOutboxRelay(&mockBeginner{}, publisher, nil)
+	msgs := []OutboxMessage{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+
+	sent, err := relay.publish(context.Background(), msgs)
+	if !errors.Is(err, publishErr) {
+		t.Errorf("Expected publish error, got %v", err)
+	}
+	if len(sent) != 2 || sent[0] != 1 || sent[1] != 2 {
+		t.Errorf("Expected messages 1 and 2 to be sent, got %v", sent)
+	}
+	if len(published) != 2 {
+		t.Errorf("Expected message 4 not to be published after a failure, got %v", published)
+	}
+
+	stats := relay.Stats()
+	if stats.Published != 2 || stats.Failed != 1 {
+		t.Errorf("Expected 2 published and 1 failed, got %+v", stats)
+	}
+}
+
+func TestOutboxRelay(t *testing.T) {
+	conn := GetTestConnection(NewMockQuerier)
+	if conn == nil {
+		t.Skip("TEST_DATABASE_URL not set, skipping integration test")
+		return
+	}
+
+	ctx := context.Background()
+	table := "dbutil_outbox_test"
+	if _, err := conn.GetDB().Exec(ctx, OutboxCreateTableSQL(table)); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	defer func() {
+		_, _ = conn.GetDB().Exec(ctx, "DROP TABLE IF EXISTS dbutil_outbox_test")
+	}()
+
+	for _, topic := range []string{"a", "b", "c"} {
+		if err := EnqueueOutbox(ctx, conn.GetDB(), table, topic, map[string]string{"topic": topic}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	var topics []string
+	publisher := PublisherFunc(func(ctx context.Context, msg OutboxMessage) error {
+		topics = append(topics, msg.Topic)
+		return nil
+	})
+
+	relay, err := NewOutboxRelay(conn.GetDB(), publisher, &OutboxRelayConfig{Table: table, BatchSize: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, expected := range []int{2, 1, 0} {
+		n, err := relay.RelayBatch(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if n != expected {
+			t.Errorf("Expected %d messages relayed, got %d", expected, n)
+		}
+	}
+
+	if strings.Join(topics, ",") != "a,b,c" {
+		t.Errorf("Expected topics a,b,c in order, got %v", topics)
+	}
+}
+
+func TestOutboxRelayDeadLetters(t *testing.T) {
+	conn := GetTestConnection(NewMockQuerier)
+	if conn == nil {
+		t.Skip("TEST_DATABASE_URL not set, skipping integration test")
+		return
+	}
+
+	ctx := context.Background()
+	table := "dbutil_outbox_dead_letter_test"
+	if _, err := conn.GetDB().Exec(ctx, OutboxCreateTableSQL(table)); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	defer func() {
+		_, _ = conn.GetDB().Exec(ctx, "DROP TABLE IF EXISTS dbutil_outbox_dead_letter_test")
+	}()
+
+	for _, topic := range []string{"poison", "ok"} {
+		if err := EnqueueOutbox(ctx, conn.GetDB(), table, topic, map[string]string{"topic": topic}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	publisher := PublisherFunc(func(ctx context.Context, msg OutboxMessage) error {
+		if msg.Topic == "poison" {
+			return errors.New("rejected by broker")
+		}
+		return nil
+	})
+	relay, err := NewOutboxRelay(conn.GetDB(), publisher, &OutboxRelayConfig{Table: table, MaxAttempts: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The poison message blocks the queue until it reaches MaxAttempts
+	for i := 0; i < 2; i++ {
+		if n, err := relay.RelayBatch(ctx); err == nil || n != 0 {
+			t.Fatalf("Expected poll %d to fail on the poison message, got %d, %v", i, n, err)
+		}
+	}
+	if n, err := relay.RelayBatch(ctx); err != nil || n != 1 {
+		t.Fatalf("Expected the next message to be published, got %d, %v", n, err)
+	}
+
+	var attempts int
+	var lastError string
+	if err := conn.GetDB().QueryRow(ctx, "SELECT attempts, last_error FROM dbutil_outbox_dead_letter_test WHERE topic = 'poison' AND sent_at IS NULL").Scan(&attempts, &lastError); err != nil {
+		t.Fatalf("Expected dead letter to stay in the table, got %v", err)
+	}
+	if attempts != 2 || !strings.Contains(lastError, "rejected by broker") {
+		t.Errorf("Expected 2 attempts and the broker error, got %d, %q", attempts, lastError)
+	}
+
+	// Sent messages are pruned once they are older than the retention
+	if n, err := relay.PruneSent(ctx, 0); err != nil || n != 1 {
+		t.Errorf("Expected 1 sent message pruned, got %d, %v", n, err)
+	}
+}
+
+// mockBeginner is a Beginner that is never expected to be called
+type mockBeginner struct{}
+
+func (m *mockBeginner) Begin(ctx context.Context) (pgx.Tx, error) {
+	return nil, errors.New("not implemented")
+}
@@ -0,0 +1,114 @@
+package dbutil
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Page size limits applied to PaginationParams
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// HasID is implemented by rows that can be paginated by their UUID primary key
+type HasID interface {
+	GetID() uuid.UUID
+}
+
+// PaginationParams requests a page of results ordered by ID.
+// Cursor is the NextCursor of the previous page, or empty for the first page.
+type PaginationParams struct {
+	Limit  int
+	Cursor string
+}
+
+// PaginationResult is a page of results and the cursor for the next page
+type PaginationResult[T any] struct {
+	Items      []T
+	NextCursor string
+	HasMore    bool
+}
+
+// cursorIDKey is the key name used for ID cursors
+const cursorIDKey = "id"
+
+// EncodeID encodes an ID as a signed cursor for ID-ordered pagination
+func (c *CursorCodec) EncodeID(id uuid.UUID) (string, error) {
+	return c.Encode(CursorKey{Name: cursorIDKey, Value: id})
+}
+
+// DecodeID decodes a cursor produced by EncodeID. Errors are ValidationErrors
+// wrapping ErrInvalidCursor.
+func (c *CursorCodec) DecodeID(cursor string) (uuid.UUID, error) {
+	keys, err := c.Decode(cursor)
+	if err != nil {
+		return uuid.Nil, NewValidationError("Pagination", "decode cursor", "cursor", "invalid cursor", err)
+	}
+	id, err := CursorValue[uuid.UUID](keys, cursorIDKey)
+	if err != nil {
+		return uuid.Nil, NewValidationError("Pagination", "decode cursor", "cursor", "invalid id", err)
+	}
+	return id, nil
+}
+
+// pageLimit returns the effective page size for params
+func (p PaginationParams) pageLimit() int {
+	if p.Limit <= 0 {
+		return DefaultPageSize
+	}
+	if p.Limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return p.Limit
+}
+
+// PaginateSlice applies keyset pagination to already-loaded items, matching a
+// `WHERE id > $cursor ORDER BY id LIMIT $n` query. Cursors are signed with codec.
+// The input slice is not modified.
+func PaginateSlice[T HasID](codec *CursorCodec, items []T, params PaginationParams) (*PaginationResult[T], error) {
+	if codec == nil {
+		return nil, fmt.Errorf("cursor codec cannot be nil")
+	}
+	limit := params.pageLimit()
+
+	sorted := make([]T, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].GetID(), sorted[j].GetID()
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+
+	start := 0
+	if params.Cursor != "" {
+		after, err := codec.DecodeID(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		start = sort.Search(len(sorted), func(i int) bool {
+			id := sorted[i].GetID()
+			return bytes.Compare(id[:], after[:]) > 0
+		})
+	}
+
+	end := start + limit
+	if end > len(sorted) {
+		end = len(sorted)
+	}
+
+	result := &PaginationResult[T]{
+		Items:   sorted[start:end],
+		HasMore: end < len(sorted),
+	}
+	if result.HasMore {
+		cursor, err := codec.EncodeID(sorted[end-1].GetID())
+		if err != nil {
+			return nil, err
+		}
+		result.NextCursor = cursor
+	}
+	return result, nil
+}
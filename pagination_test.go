@@ -0,0 +1,112 @@
+package dbutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type testItem struct {
+	ID uuid.UUID
+}
+
+func (i testItem) GetID() uuid.UUID {
+	return i.ID
+}
+
+func newTestItems(n int) []testItem {
+	items := make([]testItem, n)
+	for i := range items {
+		var id uuid.UUID
+		id[15] = byte(i + 1)
+		items[i] = testItem{ID: id}
+	}
+	return items
+}
+
+func TestCursorCodecID(t *testing.T) {
+	codec, _ := NewCursorCodec([]byte("test-secret"))
+	id := uuid.New()
+	cursor, err := codec.EncodeID(id)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	decoded, err := codec.DecodeID(cursor)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if decoded != id {
+		t.Errorf("Expected %s, got %s", id, decoded)
+	}
+
+	if _, err := codec.DecodeID("not a cursor!"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+	var validationErr *ValidationError
+	other, _ := codec.Encode(CursorKey{Name: "created_at", Value: "2024-01-01"})
+	if _, err := codec.DecodeID(other); !errors.As(err, &validationErr) {
+		t.Errorf("Expected *ValidationError for cursor without id, got %v", err)
+	}
+}
+
+func TestPaginateSlice(t *testing.T) {
+	items := newTestItems(5)
+	// Reverse the input to check that pages are ordered by ID
+	reversed := []testItem{items[4], items[3], items[2], items[1], items[0]}
+	codec, _ := NewCursorCodec([]byte("test-secret"))
+
+	page, err := PaginateSlice(codec, reversed, PaginationParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[0] != items[0] || page.Items[1] != items[1] {
+		t.Errorf("Expected first two items, got %v", page.Items)
+	}
+	if !page.HasMore || page.NextCursor == "" {
+		t.Error("Expected more pages")
+	}
+	if reversed[0] != items[4] {
+		t.Error("Expected input slice to be left unmodified")
+	}
+
+	page, err = PaginateSlice(codec, reversed, PaginationParams{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[0] != items[2] {
+		t.Errorf("Expected items 3 and 4, got %v", page.Items)
+	}
+
+	page, err = PaginateSlice(codec, reversed, PaginationParams{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0] != items[4] {
+		t.Errorf("Expected last item, got %v", page.Items)
+	}
+	if page.HasMore || page.NextCursor != "" {
+		t.Error("Expected no more pages")
+	}
+
+	// Test default and maximum limits
+	page, _ = PaginateSlice(codec, newTestItems(150), PaginationParams{})
+	if len(page.Items) != DefaultPageSize {
+		t.Errorf("Expected %d items, got %d", DefaultPageSize, len(page.Items))
+	}
+	page, _ = PaginateSlice(codec, newTestItems(150), PaginationParams{Limit: 1000})
+	if len(page.Items) != MaxPageSize {
+		t.Errorf("Expected %d items, got %d", MaxPageSize, len(page.Items))
+	}
+
+	if _, err := PaginateSlice(codec, items, PaginationParams{Cursor: "!!"}); err == nil {
+		t.Error("Expected error for invalid cursor")
+	}
+
+	// Cursors signed with another secret are rejected
+	other, _ := NewCursorCodec([]byte("other-secret"))
+	forged, _ := other.EncodeID(items[3].ID)
+	if _, err := PaginateSlice(codec, items, PaginationParams{Cursor: forged}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
@@ -0,0 +1,387 @@
+// Package qb is a lightweight, typed SELECT query builder for the dynamic-query cases
+// that static sqlc queries can't cover (optional filters, user-selected ordering).
+//
+// Tables are described once with their columns, and every column used in a query is
+// checked against that metadata, so user input can only pick from real columns:
+//
+//	var Users = qb.NewTable("users", "id", "email", "created_at")
+//
+//	sql, args, err := qb.Select(Users).
+//	    Where(qb.Eq(Users.Col("email"), email)).
+//	    OrderBy(qb.Desc(Users.Col("created_at"))).
+//	    Limit(10).
+//	    Build()
+//	rows, err := conn.GetDB().Query(ctx, sql, args...)
+//
+// Values are always bound as $n placeholders and identifiers are always quoted.
+package qb
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Table describes a database table and its columns
+type Table struct {
+	Schema  string
+	Name    string
+	columns []string
+	known   map[string]bool
+}
+
+// NewTable describes a table with the given columns.
+// The name may be schema-qualified (e.g. "billing.invoices").
+func NewTable(name string, columns ...string) *Table {
+	t := &Table{
+		Name:    name,
+		columns: columns,
+		known:   make(map[string]bool, len(columns)),
+	}
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		t.Schema = name[:i]
+		t.Name = name[i+1:]
+	}
+	for _, c := range columns {
+		t.known[c] = true
+	}
+	return t
+}
+
+// Col returns a reference to a column of the table.
+// Unknown columns are reported as an error when the query is built.
+func (t *Table) Col(name string) Column {
+	return Column{Name: name, table: t}
+}
+
+// Columns returns all column references of the table in declaration order
+func (t *Table) Columns() []Column {
+	cols := make([]Column, len(t.columns))
+	for i, name := range t.columns {
+		cols[i] = t.Col(name)
+	}
+	return cols
+}
+
+// HasColumn reports whether the table has a column with the given name
+func (t *Table) HasColumn(name string) bool {
+	return t.known[name]
+}
+
+// quoted returns the quoted, optionally schema-qualified table name
+func (t *Table) quoted() string {
+	if t.Schema != "" {
+		return pgx.Identifier{t.Schema, t.Name}.Sanitize()
+	}
+	return pgx.Identifier{t.Name}.Sanitize()
+}
+
+// Column references a column of a Table
+type Column struct {
+	Name  string
+	table *Table
+}
+
+// quoted returns the quoted column name
+func (c Column) quoted() string {
+	return pgx.Identifier{c.Name}.Sanitize()
+}
+
+// Condition is a WHERE clause predicate
+type Condition interface {
+	build(b *builder) string
+}
+
+// Direction is a sort direction
+type Direction string
+
+const (
+	Ascending  Direction = "ASC"
+	Descending Direction = "DESC"
+)
+
+// Order is an ORDER BY term
+type Order struct {
+	Column    Column
+	Direction Direction
+}
+
+// Asc orders by col ascending
+func Asc(col Column) Order {
+	return Order{Column: col, Direction: Ascending}
+}
+
+// Desc orders by col descending
+func Desc(col Column) Order {
+	return Order{Column: col, Direction: Descending}
+}
+
+// SelectBuilder builds a SELECT statement for a single table
+type SelectBuilder struct {
+	table   *Table
+	columns []Column
+	where   []Condition
+	orders  []Order
+	limit   int
+	offset  int
+}
+
+// Select starts a SELECT on table. With no columns, all table columns are selected.
+func Select(table *Table, columns ...Column) *SelectBuilder {
+	if len(columns) == 0 {
+		columns = table.Columns()
+	}
+	return &SelectBuilder{table: table, columns: columns}
+}
+
+// Where adds conditions, combined with AND
+func (s *SelectBuilder) Where(conds ...Condition) *SelectBuilder {
+	s.where = append(s.where, conds...)
+	return s
+}
+
+// OrderBy adds ORDER BY terms
+func (s *SelectBuilder) OrderBy(orders ...Order) *SelectBuilder {
+	s.orders = append(s.orders, orders...)
+	return s
+}
+
+// Limit sets the LIMIT (0 means no limit)
+func (s *SelectBuilder) Limit(n int) *SelectBuilder {
+	s.limit = n
+	return s
+}
+
+// Offset sets the OFFSET (0 means no offset)
+func (s *SelectBuilder) Offset(n int) *SelectBuilder {
+	s.offset = n
+	return s
+}
+
+// Build returns the SQL statement and its arguments
+func (s *SelectBuilder) Build() (string, []interface{}, error) {
+	b := &builder{table: s.table}
+	if len(s.columns) == 0 {
+		b.fail(fmt.Errorf("qb: no columns selected from table %q", s.table.Name))
+	}
+
+	cols := make([]string, len(s.columns))
+	for i, c := range s.columns {
+		cols[i] = b.column(c)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	sb.WriteString(strings.Join(cols, ", "))
+	sb.WriteString(" FROM ")
+	sb.WriteString(s.table.quoted())
+
+	if len(s.where) > 0 {
+		conds := make([]string, len(s.where))
+		for i, c := range s.where {
+			conds[i] = b.condition(c)
+		}
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(conds, " AND "))
+	}
+
+	if len(s.orders) > 0 {
+		terms := make([]string, len(s.orders))
+		for i, o := range s.orders {
+			if o.Direction != Ascending && o.Direction != Descending {
+				b.fail(fmt.Errorf("qb: invalid sort direction %q", o.Direction))
+			}
+			terms[i] = b.column(o.Column) + " " + string(o.Direction)
+		}
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(terms, ", "))
+	}
+
+	if s.limit > 0 {
+		sb.WriteString(" LIMIT ")
+		sb.WriteString(b.arg(s.limit))
+	}
+	if s.offset > 0 {
+		sb.WriteString(" OFFSET ")
+		sb.WriteString(b.arg(s.offset))
+	}
+
+	if len(b.errs) > 0 {
+		return "", nil, errors.Join(b.errs...)
+	}
+	return sb.String(), b.args, nil
+}
+
+// builder accumulates placeholders, arguments and validation errors while building
+type builder struct {
+	table *Table
+	args  []interface{}
+	errs  []error
+}
+
+// arg binds a value and returns its placeholder
+func (b *builder) arg(v interface{}) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// column validates a column against the queried table and returns it quoted
+func (b *builder) column(c Column) string {
+	if c.table != nil && c.table != b.table {
+		b.fail(fmt.Errorf("qb: column %q belongs to table %q, not %q", c.Name, c.table.Name, b.table.Name))
+	} else if !b.table.HasColumn(c.Name) {
+		b.fail(fmt.Errorf("qb: unknown column %q for table %q", c.Name, b.table.Name))
+	}
+	return c.quoted()
+}
+
+// condition builds c, failing on a nil condition
+func (b *builder) condition(c Condition) string {
+	if c == nil {
+		b.fail(errors.New("qb: nil condition"))
+		return ""
+	}
+	return c.build(b)
+}
+
+// fail records a build error
+func (b *builder) fail(err error) {
+	b.errs = append(b.errs, err)
+}
+
+// --- Conditions ---
+
+type comparison struct {
+	col   Column
+	op    string
+	value interface{}
+}
+
+func (c comparison) build(b *builder) string {
+	return b.column(c.col) + " " + c.op + " " + b.arg(c.value)
+}
+
+// Eq matches rows where col = value
+func Eq(col Column, value interface{}) Condition {
+	return comparison{col: col, op: "=", value: value}
+}
+
+// NotEq matches rows where col <> value
+func NotEq(col Column, value interface{}) Condition {
+	return comparison{col: col, op: "<>", value: value}
+}
+
+// Lt matches rows where col < value
+func Lt(col Column, value interface{}) Condition {
+	return comparison{col: col, op: "<", value: value}
+}
+
+// Lte matches rows where col <= value
+func Lte(col Column, value interface{}) Condition {
+	return comparison{col: col, op: "<=", value: value}
+}
+
+// Gt matches rows where col > value
+func Gt(col Column, value interface{}) Condition {
+	return comparison{col: col, op: ">", value: value}
+}
+
+// Gte matches rows where col >= value
+func Gte(col Column, value interface{}) Condition {
+	return comparison{col: col, op: ">=", value: value}
+}
+
+// Like matches rows where col LIKE pattern
+func Like(col Column, pattern string) Condition {
+	return comparison{col: col, op: "LIKE", value: pattern}
+}
+
+// ILike matches rows where col ILIKE pattern
+func ILike(col Column, pattern string) Condition {
+	return comparison{col: col, op: "ILIKE", value: pattern}
+}
+
+type anyOf struct {
+	col    Column
+	values interface{}
+}
+
+func (c anyOf) build(b *builder) string {
+	return b.column(c.col) + " = ANY(" + b.arg(c.values) + ")"
+}
+
+// In matches rows where col is one of values, which must be a slice (e.g. []uuid.UUID).
+// It is rendered as col = ANY($n), so the statement text doesn't depend on the slice length.
+func In(col Column, values interface{}) Condition {
+	return anyOf{col: col, values: values}
+}
+
+type nullCheck struct {
+	col Column
+	not bool
+}
+
+func (c nullCheck) build(b *builder) string {
+	if c.not {
+		return b.column(c.col) + " IS NOT NULL"
+	}
+	return b.column(c.col) + " IS NULL"
+}
+
+// IsNull matches rows where col IS NULL
+func IsNull(col Column) Condition {
+	return nullCheck{col: col}
+}
+
+// IsNotNull matches rows where col IS NOT NULL
+func IsNotNull(col Column) Condition {
+	return nullCheck{col: col, not: true}
+}
+
+type group struct {
+	op    string
+	conds []Condition
+}
+
+func (g group) build(b *builder) string {
+	if len(g.conds) == 0 {
+		if g.op == "OR" {
+			return "FALSE"
+		}
+		return "TRUE"
+	}
+	if len(g.conds) == 1 {
+		return b.condition(g.conds[0])
+	}
+	parts := make([]string, len(g.conds))
+	for i, c := range g.conds {
+		parts[i] = b.condition(c)
+	}
+	return "(" + strings.Join(parts, " "+g.op+" ") + ")"
+}
+
+// And matches rows satisfying every condition
+func And(conds ...Condition) Condition {
+	return group{op: "AND", conds: conds}
+}
+
+// Or matches rows satisfying at least one condition
+func Or(conds ...Condition) Condition {
+	return group{op: "OR", conds: conds}
+}
+
+type not struct {
+	cond Condition
+}
+
+func (n not) build(b *builder) string {
+	return "NOT (" + b.condition(n.cond) + ")"
+}
+
+// Not negates a condition
+func Not(cond Condition) Condition {
+	return not{cond: cond}
+}
@@ -0,0 +1,117 @@
+package qb
+
+import (
+	"strings"
+	"testing"
+)
+
+var users = NewTable("users", "id", "email", "name", "created_at", "deleted_at")
+
+func TestSelectAllColumns(t *testing.T) {
+	sql, args, err := Select(users).Build()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `SELECT "id", "email", "name", "created_at", "deleted_at" FROM "users"`
+	if sql != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, sql)
+	}
+	if len(args) != 0 {
+		t.Errorf("Expected no args, got %v", args)
+	}
+}
+
+func TestSelectWithConditions(t *testing.T) {
+	sql, args, err := Select(users, users.Col("id"), users.Col("email")).
+		Where(
+			Eq(users.Col("email"), "a@example.com"),
+			IsNull(users.Col("deleted_at")),
+			Or(Gt(users.Col("created_at"), "2024-01-01"), In(users.Col("id"), []int{1, 2})),
+		).
+		OrderBy(Desc(users.Col("created_at")), Asc(users.Col("id"))).
+		Limit(10).
+		Offset(20).
+		Build()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `SELECT "id", "email" FROM "users" WHERE "email" = $1 AND "deleted_at" IS NULL AND ("created_at" > $2 OR "id" = ANY($3)) ORDER BY "created_at" DESC, "id" ASC LIMIT $4 OFFSET $5`
+	if sql != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, sql)
+	}
+	if len(args) != 5 || args[0] != "a@example.com" || args[3] != 10 || args[4] != 20 {
+		t.Errorf("Unexpected args: %v", args)
+	}
+}
+
+func TestSchemaQualifiedTable(t *testing.T) {
+	invoices := NewTable("billing.invoices", "id", "total")
+	if invoices.Schema != "billing" || invoices.Name != "invoices" {
+		t.Errorf("Expected schema 'billing' and name 'invoices', got '%s' and '%s'", invoices.Schema, invoices.Name)
+	}
+
+	sql, _, err := Select(invoices).Where(Not(Lte(invoices.Col("total"), 0))).Build()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := `SELECT "id", "total" FROM "billing"."invoices" WHERE NOT ("total" <= $1)`
+	if sql != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, sql)
+	}
+}
+
+func TestUnknownColumns(t *testing.T) {
+	posts := NewTable("posts", "id", "title")
+
+	_, _, err := Select(users).Where(Eq(users.Col("password"), "x")).Build()
+	if err == nil || !strings.Contains(err.Error(), `unknown column "password"`) {
+		t.Errorf("Expected unknown column error, got %v", err)
+	}
+
+	_, _, err = Select(users).OrderBy(Asc(posts.Col("title"))).Build()
+	if err == nil || !strings.Contains(err.Error(), `belongs to table "posts"`) {
+		t.Errorf("Expected wrong table error, got %v", err)
+	}
+
+	_, _, err = Select(users).OrderBy(Order{Column: users.Col("id"), Direction: "SIDEWAYS"}).Build()
+	if err == nil || !strings.Contains(err.Error(), "invalid sort direction") {
+		t.Errorf("Expected invalid direction error, got %v", err)
+	}
+}
+
+func TestNoColumns(t *testing.T) {
+	empty := NewTable("empty")
+
+	_, _, err := Select(empty).Build()
+	if err == nil || !strings.Contains(err.Error(), "no columns selected") {
+		t.Errorf("Expected no columns error, got %v", err)
+	}
+}
+
+func TestNilConditions(t *testing.T) {
+	conds := []Condition{
+		nil,
+		And(Eq(users.Col("id"), 1), nil),
+		Or(nil, Eq(users.Col("id"), 1)),
+		Not(nil),
+	}
+	for i, cond := range conds {
+		_, _, err := Select(users).Where(cond).Build()
+		if err == nil || !strings.Contains(err.Error(), "nil condition") {
+			t.Errorf("Condition %d: expected nil condition error, got %v", i, err)
+		}
+	}
+}
+
+func TestIdentifierQuoting(t *testing.T) {
+	weird := NewTable("odd", `na"me`)
+	sql, _, err := Select(weird).Build()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sql != `SELECT "na""me" FROM "odd"` {
+		t.Errorf("Expected quoted identifier, got '%s'", sql)
+	}
+}
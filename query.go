@@ -0,0 +1,104 @@
+package dbutil
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type queryNameContextKey struct{}
+
+// WithQueryName returns a context that labels the next Exec/Query/QueryRow call with
+// name when reporting to the MetricsCollector and OnQuery hooks
+func WithQueryName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, queryNameContextKey{}, name)
+}
+
+// QueryNameFromContext returns the query name stored in the context, if any
+func QueryNameFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(queryNameContextKey{}).(string)
+	return name, ok && name != ""
+}
+
+// Exec executes a statement on the pool, recording it via the MetricsCollector and
+// OnQuery hooks. Errors, including allow-list rejections, are returned as *QueryError.
+func (c *Connection[T]) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	start := time.Now()
+	tag, err := c.DBTX().Exec(ctx, sql, args...)
+	return tag, c.observeQuery(ctx, "exec", sql, start, err)
+}
+
+// Query executes a query on the pool, recording it via the MetricsCollector and
+// OnQuery hooks. The recorded duration covers sending the query and receiving the
+// first response, not reading all rows. Errors are returned as *QueryError.
+func (c *Connection[T]) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	start := time.Now()
+	rows, err := c.DBTX().Query(ctx, sql, args...)
+	return rows, c.observeQuery(ctx, "query", sql, start, err)
+}
+
+// QueryRow executes a query expected to return at most one row. The query is recorded
+// via the MetricsCollector and OnQuery hooks when Scan is called, and Scan errors are
+// returned as *QueryError (errors.Is(err, pgx.ErrNoRows) still works).
+func (c *Connection[T]) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	start := time.Now()
+	return &observedRow{
+		row: c.DBTX().QueryRow(ctx, sql, args...),
+		observe: func(err error) error {
+			return c.observeQuery(ctx, "query_row", sql, start, err)
+		},
+	}
+}
+
+// observeQuery reports a finished statement and wraps err in a *QueryError
+func (c *Connection[T]) observeQuery(ctx context.Context, operation, sql string, start time.Time, err error) error {
+	duration := time.Since(start)
+
+	name, ok := QueryNameFromContext(ctx)
+	if !ok {
+		name = operation
+	}
+
+	if c.metrics != nil {
+		c.metrics.RecordQueryExecuted(name, duration, err)
+	}
+	if c.hooks != nil {
+		c.hooks.ExecuteOnQuery(ctx, QueryEvent{
+			Name:      name,
+			Operation: operation,
+			SQL:       sql,
+			Duration:  duration,
+			Err:       err,
+		})
+	}
+
+	if err != nil {
+		queryName, _ := QueryNameFromContext(ctx)
+		return NewQueryError(queryName, "", operation, err)
+	}
+	return nil
+}
+
+// observedRow reports a QueryRow call once its result is scanned.
+// Only the first Scan is reported; later calls go straight to the row.
+type observedRow struct {
+	row     pgx.Row
+	observe func(error) error
+	once    sync.Once
+}
+
+func (r *observedRow) Scan(dest ...interface{}) error {
+	observed := false
+	var err error
+	r.once.Do(func() {
+		observed = true
+		err = r.observe(r.row.Scan(dest...))
+	})
+	if !observed {
+		return r.row.Scan(dest...)
+	}
+	return err
+}
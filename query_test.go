@@ -0,0 +1,120 @@
+package dbutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestQueryNameContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := QueryNameFromContext(ctx); ok {
+		t.Error("Expected no query name in empty context")
+	}
+
+	ctx = WithQueryName(ctx, "ListActiveUsers")
+	name, ok := QueryNameFromContext(ctx)
+	if !ok || name != "ListActiveUsers" {
+		t.Errorf("Expected query name 'ListActiveUsers', got '%s'", name)
+	}
+}
+
+func TestObserveQuery(t *testing.T) {
+	metrics := &testMetricsCollector{}
+	hooks := NewConnectionHooks()
+	var events []QueryEvent
+	hooks.AddOnQuery(func(ctx context.Context, event QueryEvent) {
+		events = append(events, event)
+	})
+
+	conn := &Connection[*MockQuerier]{queries: &MockQuerier{}, metrics: metrics, hooks: hooks}
+
+	// Test success without a query name
+	row := &observedRow{
+		row: errRow{},
+		observe: func(err error) error {
+			return conn.observeQuery(context.Background(), "query_row", "SELECT 1", time.Now(), err)
+		},
+	}
+	if err := row.Scan(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	// Scanning again must not record the query a second time
+	_ = row.Scan()
+
+	// Test failure with a query name
+	ctx := WithQueryName(context.Background(), "GetUser")
+	err := conn.observeQuery(ctx, "query_row", "SELECT * FROM users WHERE id = $1", time.Now(), pgx.ErrNoRows)
+
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) {
+		t.Fatalf("Expected *QueryError, got %T", err)
+	}
+	if queryErr.Query != "GetUser" || queryErr.Operation != "query_row" {
+		t.Errorf("Expected GetUser/query_row, got %s/%s", queryErr.Query, queryErr.Operation)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Error("Expected errors.Is to find pgx.ErrNoRows")
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Expected errors.Is to match ErrNotFound")
+	}
+
+	if metrics.QueriesExecuted != 2 {
+		t.Errorf("Expected 2 queries recorded, got %d", metrics.QueriesExecuted)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 query events, got %d", len(events))
+	}
+	if events[0].Name != "query_row" || events[0].SQL != "SELECT 1" || events[0].Err != nil {
+		t.Errorf("Unexpected first event: %+v", events[0])
+	}
+	if events[1].Name != "GetUser" || !errors.Is(events[1].Err, pgx.ErrNoRows) {
+		t.Errorf("Unexpected second event: %+v", events[1])
+	}
+
+	// Test without metrics or hooks (should not panic)
+	bare := &Connection[*MockQuerier]{queries: &MockQuerier{}}
+	if err := bare.observeQuery(context.Background(), "exec", "SELECT 1", time.Now(), nil); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestConnectionExecQuery(t *testing.T) {
+	conn := GetTestConnection(NewMockQuerier)
+	if conn == nil {
+		t.Skip("TEST_DATABASE_URL not set, skipping integration test")
+		return
+	}
+
+	metrics := &testMetricsCollector{}
+	conn = conn.WithMetrics(metrics)
+	ctx := context.Background()
+
+	if _, err := conn.Exec(ctx, "SELECT 1"); err != nil {
+		t.Errorf("Expected no error from Exec, got %v", err)
+	}
+
+	rows, err := conn.Query(ctx, "SELECT generate_series(1, 3)")
+	if err != nil {
+		t.Fatalf("Expected no error from Query, got %v", err)
+	}
+	rows.Close()
+
+	var n int
+	if err := conn.QueryRow(ctx, "SELECT 42").Scan(&n); err != nil || n != 42 {
+		t.Errorf("Expected 42, got %d (err %v)", n, err)
+	}
+
+	err = conn.QueryRow(WithQueryName(ctx, "Missing"), "SELECT 1 WHERE false").Scan(&n)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("Expected pgx.ErrNoRows, got %v", err)
+	}
+
+	if metrics.QueriesExecuted != 4 {
+		t.Errorf("Expected 4 queries recorded, got %d", metrics.QueriesExecuted)
+	}
+}
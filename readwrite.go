@@ -17,6 +17,7 @@ type ReadWriteConnection[T Querier] struct {
 	writeQueries T
 	metrics      MetricsCollector
 	hooks        *ConnectionHooks
+	allowList    *StatementAllowList
 }
 
 // NewReadWriteConnection creates a new connection with separate read and write pools
@@ -46,6 +47,7 @@ func NewReadWriteConnectionWithConfig[T Querier](ctx context.Context, readDSN, w
 		writeQueries: newQueriesFunc(writePool),
 		metrics:      nil,
 		hooks:        nil,
+		allowList:    allowListFromConfigs(readConfig, writeConfig),
 	}, nil
 }
 
@@ -72,10 +74,10 @@ func (rw *ReadWriteConnection[T]) WriteDB() *pgxpool.Pool {
 // HealthCheck performs health checks on both read and write connections
 func (rw *ReadWriteConnection[T]) HealthCheck(ctx context.Context) error {
 	if err := rw.readPool.Ping(ctx); err != nil {
-		return fmt.Errorf("read pool health check failed: %w", err)
+		return NewQueryError("", "", "ping read database", err)
 	}
 	if err := rw.writePool.Ping(ctx); err != nil {
-		return fmt.Errorf("write pool health check failed: %w", err)
+		return NewQueryError("", "", "ping write database", err)
 	}
 	return nil
 }
@@ -104,6 +106,7 @@ func (rw *ReadWriteConnection[T]) WithMetrics(metrics MetricsCollector) *ReadWri
 		writeQueries: rw.writeQueries,
 		metrics:      metrics,
 		hooks:        rw.hooks,
+		allowList:    rw.allowList,
 	}
 }
 
@@ -116,14 +119,29 @@ func (rw *ReadWriteConnection[T]) WithHooks(hooks *ConnectionHooks) *ReadWriteCo
 		writeQueries: rw.writeQueries,
 		metrics:      rw.metrics,
 		hooks:        hooks,
+		allowList:    rw.allowList,
+	}
+}
+
+// WithAllowList returns a new read/write connection whose transactions only execute
+// statements in allowList
+func (rw *ReadWriteConnection[T]) WithAllowList(allowList *StatementAllowList) *ReadWriteConnection[T] {
+	return &ReadWriteConnection[T]{
+		readPool:     rw.readPool,
+		writePool:    rw.writePool,
+		readQueries:  rw.readQueries,
+		writeQueries: rw.writeQueries,
+		metrics:      rw.metrics,
+		hooks:        rw.hooks,
+		allowList:    allowList,
 	}
 }
 
 // WithTransaction executes the given function within a database transaction on the write pool
 func (rw *ReadWriteConnection[T]) WithTransaction(ctx context.Context, fn TransactionFunc[T]) error {
-	tx, err := rw.writePool.Begin(ctx)
+	tx, err := rw.begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return NewQueryError("", "", "begin transaction", err)
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
@@ -143,7 +161,7 @@ func (rw *ReadWriteConnection[T]) WithTransaction(ctx context.Context, fn Transa
 
 	// Commit the transaction
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return NewQueryError("", "", "commit transaction", err)
 	}
 
 	return nil
@@ -151,15 +169,35 @@ func (rw *ReadWriteConnection[T]) WithTransaction(ctx context.Context, fn Transa
 
 // BeginTransaction starts a new transaction on the write pool
 func (rw *ReadWriteConnection[T]) BeginTransaction(ctx context.Context) (pgx.Tx, T, error) {
-	tx, err := rw.writePool.Begin(ctx)
+	tx, err := rw.begin(ctx)
 	if err != nil {
-		return nil, *new(T), fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, *new(T), NewQueryError("", "", "begin transaction", err)
 	}
 
 	txQueries := rw.writeQueries.WithTx(tx)
 	return tx, txQueries.(T), nil
 }
 
+// begin starts a transaction on the write pool, wrapped with the allow-list if one is set
+func (rw *ReadWriteConnection[T]) begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := rw.writePool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return wrapAllowListTx(tx, rw.allowList), nil
+}
+
+// allowListFromConfigs returns the allow-list set on the write config, or else the read config
+func allowListFromConfigs(readConfig, writeConfig *Config) *StatementAllowList {
+	if writeConfig != nil && writeConfig.AllowList != nil {
+		return writeConfig.AllowList
+	}
+	if readConfig != nil {
+		return readConfig.AllowList
+	}
+	return nil
+}
+
 // WithRetry returns a new read/write connection with retry logic enabled
 func (rw *ReadWriteConnection[T]) WithRetry(config *RetryConfig) *RetryableReadWriteConnection[T] {
 	if config == nil {
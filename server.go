@@ -0,0 +1,152 @@
+package dbutil
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PostgreSQL server versions (as reported by server_version_num) at which notable
+// features became available. Use them with ServerVersionAtLeast.
+const (
+	PostgresVersion14 = 140000 // multirange types
+	PostgresVersion15 = 150000 // MERGE
+	PostgresVersion16 = 160000
+	PostgresVersion17 = 170000
+)
+
+// serverInfo caches server details shared by all copies of a Connection
+type serverInfo struct {
+	mu      sync.Mutex
+	version int
+}
+
+// ServerVersion returns the PostgreSQL server version as an integer in
+// server_version_num format (e.g. 150004 for 15.4). The version is queried once
+// and cached for the lifetime of the connection; failed lookups are not cached.
+func (c *Connection[T]) ServerVersion(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, fmt.Errorf("context cannot be nil")
+	}
+
+	if c.server == nil {
+		return queryServerVersion(ctx, c)
+	}
+
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+
+	if c.server.version > 0 {
+		return c.server.version, nil
+	}
+
+	version, err := queryServerVersion(ctx, c)
+	if err != nil {
+		return 0, err
+	}
+	c.server.version = version
+	return version, nil
+}
+
+// ServerVersionAtLeast reports whether the server version is at least minVersion
+// (in server_version_num format, e.g. PostgresVersion15)
+func (c *Connection[T]) ServerVersionAtLeast(ctx context.Context, minVersion int) (bool, error) {
+	version, err := c.ServerVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+	return version >= minVersion, nil
+}
+
+// queryServerVersion reads server_version_num from the database
+func queryServerVersion[T Querier](ctx context.Context, c *Connection[T]) (int, error) {
+	var raw string
+	if err := c.pool.QueryRow(ctx, "SHOW server_version_num").Scan(&raw); err != nil {
+		return 0, NewQueryError("", "", "query server version", err)
+	}
+	return parseServerVersion(raw)
+}
+
+// parseServerVersion parses a server_version_num value such as "150004"
+func parseServerVersion(raw string) (int, error) {
+	version, err := strconv.Atoi(raw)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid server_version_num %q", raw)
+	}
+	return version, nil
+}
+
+// FormatServerVersion converts a server_version_num value to a human-readable
+// version such as "15.4" (or "9.6.24" for pre-10 servers)
+func FormatServerVersion(version int) string {
+	if version >= 100000 {
+		return fmt.Sprintf("%d.%d", version/10000, version%10000)
+	}
+	return fmt.Sprintf("%d.%d.%d", version/10000, (version/100)%100, version%100)
+}
+
+// MissingExtensionsError is returned by VerifyExtensions when required extensions
+// are not installed in the database
+type MissingExtensionsError struct {
+	Missing []string
+}
+
+func (e *MissingExtensionsError) Error() string {
+	return fmt.Sprintf("missing required PostgreSQL extensions: %s", strings.Join(e.Missing, ", "))
+}
+
+// InstalledExtensions returns the names of the extensions installed in the database
+func (c *Connection[T]) InstalledExtensions(ctx context.Context) ([]string, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+
+	rows, err := c.pool.Query(ctx, "SELECT extname FROM pg_extension ORDER BY extname")
+	if err != nil {
+		return nil, NewQueryError("", "pg_extension", "query", err)
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, NewQueryError("", "pg_extension", "query", err)
+	}
+	return names, nil
+}
+
+// VerifyExtensions checks that every required extension (e.g. "pgcrypto", "citext",
+// "vector", "postgis") is installed. It is intended as a startup check and returns a
+// *MissingExtensionsError listing every missing extension.
+func (c *Connection[T]) VerifyExtensions(ctx context.Context, required ...string) error {
+	if len(required) == 0 {
+		return nil
+	}
+
+	installed, err := c.InstalledExtensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	if missing := missingExtensions(installed, required); len(missing) > 0 {
+		return &MissingExtensionsError{Missing: missing}
+	}
+	return nil
+}
+
+// missingExtensions returns the required extensions that are not installed
+func missingExtensions(installed, required []string) []string {
+	have := make(map[string]bool, len(installed))
+	for _, name := range installed {
+		have[name] = true
+	}
+
+	var missing []string
+	for _, name := range required {
+		if !have[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,119 @@
+package dbutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	version, err := parseServerVersion("150004")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if version != 150004 {
+		t.Errorf("Expected 150004, got %d", version)
+	}
+
+	for _, raw := range []string{"", "15.4", "-1"} {
+		if _, err := parseServerVersion(raw); err == nil {
+			t.Errorf("Expected error for %q", raw)
+		}
+	}
+}
+
+func TestFormatServerVersion(t *testing.T) {
+	cases := map[int]string{
+		150004: "15.4",
+		170000: "17.0",
+		90624:  "9.6.24",
+	}
+	for version, expected := range cases {
+		if got := FormatServerVersion(version); got != expected {
+			t.Errorf("Expected '%s' for %d, got '%s'", expected, version, got)
+		}
+	}
+}
+
+func TestServerVersionCached(t *testing.T) {
+	// A cached version is returned without touching the (nil) pool
+	conn := &Connection[*MockQuerier]{server: &serverInfo{version: 150004}}
+
+	version, err := conn.ServerVersion(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if version != 150004 {
+		t.Errorf("Expected 150004, got %d", version)
+	}
+
+	ok, err := conn.ServerVersionAtLeast(context.Background(), PostgresVersion15)
+	if err != nil || !ok {
+		t.Errorf("Expected version to be at least 15, got %v (err=%v)", ok, err)
+	}
+
+	ok, _ = conn.ServerVersionAtLeast(context.Background(), PostgresVersion16)
+	if ok {
+		t.Error("Expected version to be below 16")
+	}
+
+	// The cache is shared with derived connections
+	if v, _ := conn.WithMetrics(nil).ServerVersion(context.Background()); v != 150004 {
+		t.Errorf("Expected derived connection to share cached version, got %d", v)
+	}
+}
+
+func TestServerVersionIntegration(t *testing.T) {
+	conn := GetTestConnection(NewMockQuerier)
+	if conn == nil {
+		t.Skip("TEST_DATABASE_URL not set, skipping integration test")
+		return
+	}
+
+	version, err := conn.ServerVersion(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if version < 100000 {
+		t.Errorf("Expected a modern server version, got %d", version)
+	}
+}
+
+func TestMissingExtensions(t *testing.T) {
+	missing := missingExtensions([]string{"plpgsql", "pgcrypto"}, []string{"pgcrypto", "citext", "vector"})
+	if len(missing) != 2 || missing[0] != "citext" || missing[1] != "vector" {
+		t.Errorf("Expected [citext vector], got %v", missing)
+	}
+
+	if missing := missingExtensions([]string{"plpgsql"}, []string{"plpgsql"}); len(missing) != 0 {
+		t.Errorf("Expected no missing extensions, got %v", missing)
+	}
+
+	err := error(&MissingExtensionsError{Missing: []string{"citext", "vector"}})
+	expectedMsg := "missing required PostgreSQL extensions: citext, vector"
+	if err.Error() != expectedMsg {
+		t.Errorf("Expected message '%s', got '%s'", expectedMsg, err.Error())
+	}
+}
+
+func TestVerifyExtensionsIntegration(t *testing.T) {
+	conn := GetTestConnection(NewMockQuerier)
+	if conn == nil {
+		t.Skip("TEST_DATABASE_URL not set, skipping integration test")
+		return
+	}
+
+	ctx := context.Background()
+	if err := conn.VerifyExtensions(ctx, "plpgsql"); err != nil {
+		t.Errorf("Expected plpgsql to be installed, got %v", err)
+	}
+
+	err := conn.VerifyExtensions(ctx, "dbutil_nonexistent_extension")
+	var missingErr *MissingExtensionsError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("Expected *MissingExtensionsError, got %v", err)
+	}
+	if len(missingErr.Missing) != 1 || missingErr.Missing[0] != "dbutil_nonexistent_extension" {
+		t.Errorf("Unexpected missing extensions: %v", missingErr.Missing)
+	}
+}
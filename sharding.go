@@ -0,0 +1,289 @@
+package dbutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrMissingShardKey is returned when a shard key is required but not present in the context
+var ErrMissingShardKey = errors.New("shard key not found in context")
+
+// defaultVirtualNodes is the number of points each shard occupies on the hash ring
+const defaultVirtualNodes = 128
+
+type shardKeyContextKey struct{}
+
+// WithShardKey returns a context carrying the shard key used by ShardedConnection.ForContext
+func WithShardKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, shardKeyContextKey{}, key)
+}
+
+// ShardKeyFromContext returns the shard key stored in the context, if any.
+// An empty key is reported as missing.
+func ShardKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(shardKeyContextKey{}).(string)
+	return key, ok && key != ""
+}
+
+// ShardedConnection maps shard keys to one of N connections using consistent hashing.
+// Each shard is a regular Connection with its own pool and sqlc queries.
+type ShardedConnection[T Querier] struct {
+	shards []*Connection[T]
+	ring   *hashRing
+}
+
+// NewShardedConnection creates one connection per DSN and distributes keys across them.
+// The order of dsns defines the shard indexes and must be stable between deployments.
+func NewShardedConnection[T Querier](ctx context.Context, dsns []string, newQueriesFunc func(*pgxpool.Pool) T, cfg *Config) (*ShardedConnection[T], error) {
+	if len(dsns) == 0 {
+		return nil, fmt.Errorf("at least one shard DSN is required")
+	}
+
+	shards := make([]*Connection[T], 0, len(dsns))
+	for i, dsn := range dsns {
+		conn, err := NewConnectionWithConfig(ctx, dsn, newQueriesFunc, cfg)
+		if err != nil {
+			for _, shard := range shards {
+				shard.Close()
+			}
+			return nil, fmt.Errorf("failed to create shard %d: %w", i, err)
+		}
+		shards = append(shards, conn)
+	}
+
+	return NewShardedConnectionFromConnections(shards...)
+}
+
+// NewShardedConnectionFromConnections builds a sharded connection from existing connections.
+// The order of conns defines the shard indexes and must be stable between deployments.
+func NewShardedConnectionFromConnections[T Querier](conns ...*Connection[T]) (*ShardedConnection[T], error) {
+	if len(conns) == 0 {
+		return nil, fmt.Errorf("at least one shard connection is required")
+	}
+
+	return &ShardedConnection[T]{
+		shards: conns,
+		ring:   newHashRing(len(conns), defaultVirtualNodes),
+	}, nil
+}
+
+// ShardIndex returns the index of the shard responsible for key
+func (s *ShardedConnection[T]) ShardIndex(key string) int {
+	return s.ring.get(key)
+}
+
+// Shard returns the connection responsible for key
+func (s *ShardedConnection[T]) Shard(key string) *Connection[T] {
+	return s.shards[s.ShardIndex(key)]
+}
+
+// ForContext returns the connection responsible for the shard key stored in ctx.
+// It returns ErrMissingShardKey if the context has no shard key.
+func (s *ShardedConnection[T]) ForContext(ctx context.Context) (*Connection[T], error) {
+	key, ok := ShardKeyFromContext(ctx)
+	if !ok {
+		return nil, ErrMissingShardKey
+	}
+	return s.Shard(key), nil
+}
+
+// Queries returns the sqlc queries for the shard responsible for key
+func (s *ShardedConnection[T]) Queries(key string) T {
+	return s.Shard(key).Queries()
+}
+
+// WithTransaction executes fn within a transaction on the shard responsible for key
+func (s *ShardedConnection[T]) WithTransaction(ctx context.Context, key string, fn TransactionFunc[T]) error {
+	return s.Shard(key).WithTransaction(ctx, fn)
+}
+
+// Shards returns all shard connections in index order
+func (s *ShardedConnection[T]) Shards() []*Connection[T] {
+	return s.shards
+}
+
+// NumShards returns the number of shards
+func (s *ShardedConnection[T]) NumShards() int {
+	return len(s.shards)
+}
+
+// HealthCheck performs health checks on every shard
+func (s *ShardedConnection[T]) HealthCheck(ctx context.Context) error {
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+	for i, shard := range s.shards {
+		if err := shard.pool.Ping(ctx); err != nil {
+			return NewQueryError("", "", fmt.Sprintf("ping shard %d", i), err)
+		}
+	}
+	return nil
+}
+
+// IsReady checks if every shard is ready
+func (s *ShardedConnection[T]) IsReady(ctx context.Context) bool {
+	return s.HealthCheck(ctx) == nil
+}
+
+// Close closes every shard pool
+func (s *ShardedConnection[T]) Close() {
+	for _, shard := range s.shards {
+		shard.Close()
+	}
+}
+
+// hashRing implements consistent hashing with virtual nodes so that adding a shard
+// only remaps roughly 1/N of the keys
+type hashRing struct {
+	points []uint64
+	owners map[uint64]int
+}
+
+// newHashRing creates a ring with the given number of shards and virtual nodes per shard
+func newHashRing(shards, virtualNodes int) *hashRing {
+	r := &hashRing{
+		points: make([]uint64, 0, shards*virtualNodes),
+		owners: make(map[uint64]int, shards*virtualNodes),
+	}
+	for shard := 0; shard < shards; shard++ {
+		for v := 0; v < virtualNodes; v++ {
+			point := hashKey("shard-" + strconv.Itoa(shard) + "-" + strconv.Itoa(v))
+			if _, exists := r.owners[point]; exists {
+				continue
+			}
+			r.owners[point] = shard
+			r.points = append(r.points, point)
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// get returns the shard owning key
+func (r *hashRing) get(key string) int {
+	h := hashKey(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// hashKey returns the 64-bit FNV-1a hash of key, passed through a finalizer so that
+// similar keys (e.g. "user-1", "user-2") spread evenly around the ring
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// ScatterGatherOptions controls how QueryAllShards executes and merges results
+type ScatterGatherOptions[R any] struct {
+	// MaxConcurrency bounds how many shards are queried at once (0 means all shards)
+	MaxConcurrency int
+	// Less, if set, sorts the merged results; otherwise results are concatenated in shard order
+	Less func(a, b R) bool
+}
+
+// ShardQueryFunc queries a single shard and returns its results
+type ShardQueryFunc[T Querier, R any] func(ctx context.Context, shard int, conn *Connection[T]) ([]R, error)
+
+// QueryAllShards executes fn on every shard concurrently and merges the results.
+// If any shard fails, the remaining shards are cancelled and the first error is returned.
+func QueryAllShards[T Querier, R any](ctx context.Context, s *ShardedConnection[T], fn ShardQueryFunc[T, R], opts *ScatterGatherOptions[R]) ([]R, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("shard query function cannot be nil")
+	}
+	if opts == nil {
+		opts = &ScatterGatherOptions[R]{}
+	}
+
+	limit := opts.MaxConcurrency
+	if limit <= 0 || limit > len(s.shards) {
+		limit = len(s.shards)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]R, len(s.shards))
+	errs := make([]error, len(s.shards))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i, shard := range s.shards {
+		wg.Add(1)
+		go func(i int, shard *Connection[T]) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			rows, err := fn(ctx, i, shard)
+			if err != nil {
+				errs[i] = NewQueryError("", "", fmt.Sprintf("query shard %d", i), err)
+				cancel()
+				return
+			}
+			results[i] = rows
+		}(i, shard)
+	}
+	wg.Wait()
+
+	if err := firstShardError(errs); err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, rows := range results {
+		total += len(rows)
+	}
+	merged := make([]R, 0, total)
+	for _, rows := range results {
+		merged = append(merged, rows...)
+	}
+
+	if opts.Less != nil {
+		sort.SliceStable(merged, func(i, j int) bool { return opts.Less(merged[i], merged[j]) })
+	}
+
+	return merged, nil
+}
+
+// firstShardError returns the first shard error, preferring real failures over the
+// cancellation errors they caused on other shards
+func firstShardError(errs []error) error {
+	var cancelled error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, context.Canceled) && cancelled == nil {
+			cancelled = err
+			continue
+		}
+		return err
+	}
+	return cancelled
+}
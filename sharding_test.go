@@ -0,0 +1,213 @@
+package dbutil
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHashRingDeterministic(t *testing.T) {
+	ring := newHashRing(4, defaultVirtualNodes)
+	other := newHashRing(4, defaultVirtualNodes)
+
+	for i := 0; i < 1000; i++ {
+		key := "user-" + strconv.Itoa(i)
+		if ring.get(key) != other.get(key) {
+			t.Fatalf("Expected key %s to map to the same shard on identical rings", key)
+		}
+	}
+}
+
+func TestHashRingDistribution(t *testing.T) {
+	ring := newHashRing(4, defaultVirtualNodes)
+	counts := make(map[int]int)
+
+	for i := 0; i < 10000; i++ {
+		counts[ring.get("user-"+strconv.Itoa(i))]++
+	}
+
+	for shard := 0; shard < 4; shard++ {
+		// Each shard should get a reasonable share of 2500 expected keys
+		if counts[shard] < 1500 || counts[shard] > 3500 {
+			t.Errorf("Expected roughly even distribution, shard %d got %d keys", shard, counts[shard])
+		}
+	}
+}
+
+func TestHashRingStability(t *testing.T) {
+	before := newHashRing(4, defaultVirtualNodes)
+	after := newHashRing(5, defaultVirtualNodes)
+
+	moved := 0
+	for i := 0; i < 10000; i++ {
+		key := "user-" + strconv.Itoa(i)
+		if before.get(key) != after.get(key) {
+			moved++
+		}
+	}
+
+	// Adding a fifth shard should move roughly 1/5 of the keys, far fewer than modulo hashing
+	if moved > 3500 {
+		t.Errorf("Expected consistent hashing to move few keys, moved %d of 10000", moved)
+	}
+}
+
+func TestShardKeyContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := ShardKeyFromContext(ctx); ok {
+		t.Error("Expected no shard key in empty context")
+	}
+	if _, ok := ShardKeyFromContext(WithShardKey(ctx, "")); ok {
+		t.Error("Expected empty shard key to be treated as missing")
+	}
+
+	ctx = WithShardKey(ctx, "tenant-1")
+	key, ok := ShardKeyFromContext(ctx)
+	if !ok || key != "tenant-1" {
+		t.Errorf("Expected shard key 'tenant-1', got '%s'", key)
+	}
+}
+
+func TestShardedConnectionRouting(t *testing.T) {
+	shards := []*Connection[*MockQuerier]{
+		{queries: &MockQuerier{}},
+		{queries: &MockQuerier{}},
+		{queries: &MockQuerier{}},
+	}
+
+	sharded, err := NewShardedConnectionFromConnections(shards...)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if sharded.NumShards() != 3 {
+		t.Errorf("Expected 3 shards, got %d", sharded.NumShards())
+	}
+
+	idx := sharded.ShardIndex("tenant-1")
+	if sharded.Shard("tenant-1") != shards[idx] {
+		t.Error("Expected Shard to return the connection at ShardIndex")
+	}
+
+	conn, err := sharded.ForContext(WithShardKey(context.Background(), "tenant-1"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if conn != shards[idx] {
+		t.Error("Expected ForContext to route using the context shard key")
+	}
+
+	if _, err := sharded.ForContext(context.Background()); !errors.Is(err, ErrMissingShardKey) {
+		t.Errorf("Expected ErrMissingShardKey, got %v", err)
+	}
+
+	if _, err := NewShardedConnectionFromConnections[*MockQuerier](); err == nil {
+		t.Error("Expected error when no connections are given")
+	}
+}
+
+func newTestShardedConnection(t *testing.T, n int) *ShardedConnection[*MockQuerier] {
+	shards := make([]*Connection[*MockQuerier], n)
+	for i := range shards {
+		shards[i] = &Connection[*MockQuerier]{queries: &MockQuerier{}}
+	}
+	sharded, err := NewShardedConnectionFromConnections(shards...)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return sharded
+}
+
+func TestQueryAllShards(t *testing.T) {
+	sharded := newTestShardedConnection(t, 3)
+
+	fn := func(ctx context.Context, shard int, conn *Connection[*MockQuerier]) ([]int, error) {
+		return []int{shard + 10, shard}, nil
+	}
+
+	// Without ordering, results are concatenated in shard order
+	results, err := QueryAllShards(context.Background(), sharded, fn, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []int{10, 0, 11, 1, 12, 2}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, results)
+	}
+	for i := range expected {
+		if results[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, results)
+		}
+	}
+
+	// With ordering, results are sorted
+	results, err = QueryAllShards(context.Background(), sharded, fn, &ScatterGatherOptions[int]{
+		Less: func(a, b int) bool { return a < b },
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i-1] > results[i] {
+			t.Fatalf("Expected sorted results, got %v", results)
+		}
+	}
+}
+
+func TestQueryAllShardsBoundedConcurrency(t *testing.T) {
+	sharded := newTestShardedConnection(t, 8)
+
+	var running, maxRunning int32
+	fn := func(ctx context.Context, shard int, conn *Connection[*MockQuerier]) ([]int, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			current := atomic.LoadInt32(&maxRunning)
+			if n <= current || atomic.CompareAndSwapInt32(&maxRunning, current, n) {
+				break
+			}
+		}
+		defer atomic.AddInt32(&running, -1)
+		return []int{shard}, nil
+	}
+
+	results, err := QueryAllShards(context.Background(), sharded, fn, &ScatterGatherOptions[int]{MaxConcurrency: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results) != 8 {
+		t.Errorf("Expected 8 results, got %d", len(results))
+	}
+	if maxRunning > 2 {
+		t.Errorf("Expected at most 2 concurrent shard queries, got %d", maxRunning)
+	}
+}
+
+func TestQueryAllShardsError(t *testing.T) {
+	sharded := newTestShardedConnection(t, 3)
+	shardErr := errors.New("relation does not exist")
+
+	fn := func(ctx context.Context, shard int, conn *Connection[*MockQuerier]) ([]int, error) {
+		if shard == 1 {
+			return nil, shardErr
+		}
+		return []int{shard}, nil
+	}
+
+	results, err := QueryAllShards(context.Background(), sharded, fn, nil)
+	if !errors.Is(err, shardErr) {
+		t.Errorf("Expected shard error, got %v", err)
+	}
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) || queryErr.Operation != "query shard 1" {
+		t.Errorf("Expected *QueryError for shard 1, got %v", err)
+	}
+	if results != nil {
+		t.Errorf("Expected nil results on error, got %v", results)
+	}
+
+	if _, err := QueryAllShards[*MockQuerier, int](context.Background(), sharded, nil, nil); err == nil {
+		t.Error("Expected error for nil function")
+	}
+}
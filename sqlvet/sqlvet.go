@@ -0,0 +1,384 @@
+// Package sqlvet flags database calls whose SQL is built at runtime by string
+// concatenation or formatting, which is the usual source of SQL injection.
+//
+// It inspects calls to Exec, Query and QueryRow (as found on dbutil.Connection,
+// *pgxpool.Pool, *pgx.Conn, pgx.Tx and sqlc DBTX values) and reports the SQL
+// argument when it is:
+//   - a + concatenation that includes anything other than string literals and constants
+//   - a call to fmt.Sprintf, fmt.Sprint or fmt.Sprintln
+//   - a local variable assigned (or appended to with +=) one of the above in the
+//     enclosing function
+//
+// Identifiers quoted with pgx.Identifier{...}.Sanitize() or pgx.Identifier(...).Sanitize()
+// are treated as safe, and a finding can be suppressed with a "//sqlvet:ignore" comment
+// on the same line. Variables that are not assigned in the enclosing function
+// (parameters, package variables) are not tracked.
+//
+// Parameterized queries ($1, $2, ...), sqlc-generated queries and the qb query
+// builder are the intended alternatives. The analysis is purely syntactic and only
+// depends on the standard library; run it with the sqlvet command:
+//
+//	go run github.com/nhalm/dbutil/cmd/sqlvet ./...
+package sqlvet
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// checkedMethods are the method names whose second argument is a SQL statement
+var checkedMethods = map[string]bool{
+	"Exec":     true,
+	"Query":    true,
+	"QueryRow": true,
+}
+
+// formatFuncs are the fmt functions that build SQL strings at runtime
+var formatFuncs = map[string]bool{
+	"Sprintf":  true,
+	"Sprint":   true,
+	"Sprintln": true,
+}
+
+// Diagnostic is a single finding
+type Diagnostic struct {
+	Pos     token.Position
+	Method  string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Pos, d.Message)
+}
+
+// CheckFiles reports findings for a set of files belonging to one package.
+// Constants declared in any of the files are treated as safe. Files must be parsed
+// with parser.ParseComments for sqlvet:ignore comments to be honored.
+func CheckFiles(fset *token.FileSet, files []*ast.File) []Diagnostic {
+	consts := declaredConstants(files)
+
+	var diags []Diagnostic
+	for _, file := range files {
+		ignored := ignoredLines(fset, file)
+		bodies := functionBodies(file)
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || !checkedMethods[sel.Sel.Name] || len(call.Args) < 2 {
+				return true
+			}
+
+			pos := fset.Position(call.Args[1].Pos())
+			if ignored[pos.Line] {
+				return true
+			}
+			r := &resolver{consts: consts, scopes: enclosingBodies(bodies, call), visiting: make(map[string]bool)}
+			if reason := r.unsafeSQL(call.Args[1]); reason != "" {
+				diags = append(diags, Diagnostic{
+					Pos:     pos,
+					Method:  sel.Sel.Name,
+					Message: fmt.Sprintf("SQL passed to %s is %s; use query parameters ($1, $2, ...), sqlc queries, or the qb builder", sel.Sel.Name, reason),
+				})
+			}
+			return true
+		})
+	}
+
+	sort.Slice(diags, func(i, j int) bool {
+		if diags[i].Pos.Filename != diags[j].Pos.Filename {
+			return diags[i].Pos.Filename < diags[j].Pos.Filename
+		}
+		return diags[i].Pos.Offset < diags[j].Pos.Offset
+	})
+	return diags
+}
+
+// CheckDir parses the Go files in dir (one package) and reports findings.
+// Test files are included when includeTests is true.
+func CheckDir(dir string, includeTests bool) ([]Diagnostic, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkgs := make(map[string][]*ast.File)
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		if !includeTests && strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		pkg := file.Name.Name
+		if _, seen := pkgs[pkg]; !seen {
+			names = append(names, pkg)
+		}
+		pkgs[pkg] = append(pkgs[pkg], file)
+	}
+
+	var diags []Diagnostic
+	for _, pkg := range names {
+		diags = append(diags, CheckFiles(fset, pkgs[pkg])...)
+	}
+	return diags, nil
+}
+
+// CheckTree runs CheckDir on root and, if recursive, every directory below it,
+// skipping vendor, testdata and hidden directories
+func CheckTree(root string, recursive, includeTests bool) ([]Diagnostic, error) {
+	if !recursive {
+		return CheckDir(root, includeTests)
+	}
+
+	var diags []Diagnostic
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		found, err := CheckDir(path, includeTests)
+		if err != nil {
+			return err
+		}
+		diags = append(diags, found...)
+		return nil
+	})
+	return diags, err
+}
+
+// resolver checks SQL expressions, following local variables to their assignments
+// in the functions enclosing the call
+type resolver struct {
+	consts   map[string]bool
+	scopes   []*ast.BlockStmt // innermost first
+	visiting map[string]bool  // variables being resolved, to stop on self-references
+}
+
+// unsafeSQL returns why expr is unsafe as SQL text, or "" if it looks constant
+func (r *resolver) unsafeSQL(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return r.unsafeSQL(e.X)
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD && !r.isConstant(e) {
+			return "built by string concatenation"
+		}
+	case *ast.CallExpr:
+		if sel, ok := e.Fun.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "fmt" && formatFuncs[sel.Sel.Name] {
+				return "built with fmt." + sel.Sel.Name
+			}
+		}
+	case *ast.Ident:
+		if r.consts[e.Name] || r.visiting[e.Name] {
+			return ""
+		}
+		r.visiting[e.Name] = true
+		defer delete(r.visiting, e.Name)
+		for _, a := range r.assignments(e.Name) {
+			var reason string
+			if a.appended {
+				if !r.isConstant(a.value) {
+					reason = "built by string concatenation"
+				}
+			} else {
+				reason = r.unsafeSQL(a.value)
+			}
+			if reason != "" {
+				return fmt.Sprintf("%s (assigned to %s)", reason, e.Name)
+			}
+		}
+	}
+	return ""
+}
+
+// isConstant reports whether expr is made only of string literals, known constants,
+// sanitized pgx identifiers and local variables assigned only such values
+func (r *resolver) isConstant(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return e.Kind == token.STRING
+	case *ast.ParenExpr:
+		return r.isConstant(e.X)
+	case *ast.BinaryExpr:
+		return e.Op == token.ADD && r.isConstant(e.X) && r.isConstant(e.Y)
+	case *ast.Ident:
+		if r.consts[e.Name] || r.visiting[e.Name] {
+			return true
+		}
+		assignments := r.assignments(e.Name)
+		if len(assignments) == 0 {
+			return false
+		}
+		r.visiting[e.Name] = true
+		defer delete(r.visiting, e.Name)
+		for _, a := range assignments {
+			if !r.isConstant(a.value) {
+				return false
+			}
+		}
+		return true
+	case *ast.CallExpr:
+		return isSanitizedIdentifier(e)
+	}
+	return false
+}
+
+// isSanitizedIdentifier reports whether call is pgx.Identifier{...}.Sanitize() or
+// pgx.Identifier(...).Sanitize(), which quote identifiers safely
+func isSanitizedIdentifier(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Sanitize" || len(call.Args) != 0 {
+		return false
+	}
+
+	var typ ast.Expr
+	switch recv := sel.X.(type) {
+	case *ast.CompositeLit:
+		typ = recv.Type
+	case *ast.CallExpr:
+		typ = recv.Fun
+	default:
+		return false
+	}
+	pkgSel, ok := typ.(*ast.SelectorExpr)
+	if !ok || pkgSel.Sel.Name != "Identifier" {
+		return false
+	}
+	pkg, ok := pkgSel.X.(*ast.Ident)
+	return ok && pkg.Name == "pgx"
+}
+
+// assignment is a value assigned to a variable; appended is set for +=
+type assignment struct {
+	value    ast.Expr
+	appended bool
+}
+
+// assignments returns the values assigned to name in the innermost enclosing function
+// that assigns it. Shadowing is ignored.
+func (r *resolver) assignments(name string) []assignment {
+	for _, body := range r.scopes {
+		var found []assignment
+		ast.Inspect(body, func(n ast.Node) bool {
+			switch s := n.(type) {
+			case *ast.AssignStmt:
+				if len(s.Lhs) != len(s.Rhs) {
+					return true
+				}
+				for i, lhs := range s.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok && id.Name == name {
+						switch s.Tok {
+						case token.DEFINE, token.ASSIGN:
+							found = append(found, assignment{value: s.Rhs[i]})
+						case token.ADD_ASSIGN:
+							found = append(found, assignment{value: s.Rhs[i], appended: true})
+						}
+					}
+				}
+			case *ast.ValueSpec:
+				if len(s.Names) != len(s.Values) {
+					return true
+				}
+				for i, id := range s.Names {
+					if id.Name == name {
+						found = append(found, assignment{value: s.Values[i]})
+					}
+				}
+			}
+			return true
+		})
+		if len(found) > 0 {
+			return found
+		}
+	}
+	return nil
+}
+
+// functionBodies returns the bodies of all functions and function literals in file
+func functionBodies(file *ast.File) []*ast.BlockStmt {
+	var bodies []*ast.BlockStmt
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch f := n.(type) {
+		case *ast.FuncDecl:
+			if f.Body != nil {
+				bodies = append(bodies, f.Body)
+			}
+		case *ast.FuncLit:
+			bodies = append(bodies, f.Body)
+		}
+		return true
+	})
+	return bodies
+}
+
+// enclosingBodies returns the bodies containing node, innermost first
+func enclosingBodies(bodies []*ast.BlockStmt, node ast.Node) []*ast.BlockStmt {
+	var enclosing []*ast.BlockStmt
+	for _, body := range bodies {
+		if body.Pos() <= node.Pos() && node.End() <= body.End() {
+			enclosing = append(enclosing, body)
+		}
+	}
+	sort.Slice(enclosing, func(i, j int) bool {
+		return enclosing[i].End()-enclosing[i].Pos() < enclosing[j].End()-enclosing[j].Pos()
+	})
+	return enclosing
+}
+
+// ignoredLines returns the lines of file carrying a sqlvet:ignore comment
+func ignoredLines(fset *token.FileSet, file *ast.File) map[int]bool {
+	lines := make(map[int]bool)
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if strings.Contains(c.Text, "sqlvet:ignore") {
+				lines[fset.Position(c.Slash).Line] = true
+			}
+		}
+	}
+	return lines
+}
+
+// declaredConstants returns the names of constants declared in files, at package
+// level or inside functions. Shadowing is ignored, which errs on the side of silence.
+func declaredConstants(files []*ast.File) map[string]bool {
+	consts := make(map[string]bool)
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			gen, ok := n.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				return true
+			}
+			for _, spec := range gen.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					consts[name.Name] = true
+				}
+			}
+			return false
+		})
+	}
+	return consts
+}
@@ -0,0 +1,140 @@
+package sqlvet
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const source = `package example
+
+import (
+	"context"
+	"fmt"
+)
+
+const usersTable = "users"
+
+func run(ctx context.Context, db DB, name, order string) {
+	const local = "posts"
+
+	// Safe: literals, constants, parameters and variables assigned only those
+	db.Exec(ctx, "DELETE FROM users WHERE name = $1", name)
+	db.Query(ctx, "SELECT * FROM " + usersTable)
+	db.Query(ctx, "SELECT * FROM " + local + " LIMIT 1")
+	db.QueryRow(ctx, sqlFromElsewhere)
+	db.Exec(ctx, "TRUNCATE " + pgx.Identifier{name}.Sanitize())
+	db.Exec(ctx, "SELECT " + name) //sqlvet:ignore
+	list := "SELECT * FROM " + usersTable
+	list += " ORDER BY id"
+	db.Query(ctx, list)
+
+	// Unsafe
+	db.Exec(ctx, "DELETE FROM users WHERE name = '" + name + "'")
+	db.Query(ctx, fmt.Sprintf("SELECT * FROM users ORDER BY %s", order))
+	db.QueryRow(ctx, ("SELECT * FROM users WHERE name = " + name))
+	db.Exec(ctx, "TRUNCATE " + quoter{name}.Sanitize())
+	q := "DELETE FROM users WHERE name = '" + name + "'"
+	db.Exec(ctx, q)
+	var f = fmt.Sprintf("SELECT * FROM %s", order)
+	db.Query(ctx, f)
+	sorted := "SELECT * FROM users"
+	sorted += " ORDER BY " + order
+	db.Query(ctx, sorted)
+	go func() {
+		db.Exec(ctx, q)
+	}()
+
+	// Any Exec/Query/QueryRow method is checked, database or not
+	cache.Query(ctx, "x" + name)
+
+	// Too few arguments to carry SQL after the context
+	db.Exec("x" + name)
+}
+`
+
+func parse(t *testing.T, src string) (*token.FileSet, []*ast.File) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "example.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Failed to parse source: %v", err)
+	}
+	return fset, []*ast.File{file}
+}
+
+func TestCheckFiles(t *testing.T) {
+	fset, files := parse(t, source)
+	diags := CheckFiles(fset, files)
+
+	expected := []struct {
+		line   int
+		method string
+		reason string
+	}{
+		{25, "Exec", "string concatenation"},
+		{26, "Query", "fmt.Sprintf"},
+		{27, "QueryRow", "string concatenation"},
+		{28, "Exec", "string concatenation"},
+		{30, "Exec", "string concatenation (assigned to q)"},
+		{32, "Query", "fmt.Sprintf (assigned to f)"},
+		{35, "Query", "string concatenation (assigned to sorted)"},
+		{37, "Exec", "string concatenation (assigned to q)"},
+		{41, "Query", "string concatenation"},
+	}
+	if len(diags) != len(expected) {
+		for _, d := range diags {
+			t.Log(d)
+		}
+		t.Fatalf("Expected %d diagnostics, got %d", len(expected), len(diags))
+	}
+	for i, want := range expected {
+		d := diags[i]
+		if d.Pos.Line != want.line || d.Method != want.method || !strings.Contains(d.Message, want.reason) {
+			t.Errorf("Diagnostic %d: expected line %d %s (%s), got %s", i, want.line, want.method, want.reason, d)
+		}
+	}
+}
+
+func TestCheckTree(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "repo")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	unsafe := "package repo\n\nfunc f(db DB, id string) { db.Exec(ctx, \"DELETE FROM t WHERE id = \" + id) }\n"
+	if err := os.WriteFile(filepath.Join(sub, "repo.go"), []byte(unsafe), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "repo_test.go"), []byte(unsafe), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	diags, err := CheckTree(root, true, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(diags) != 1 {
+		t.Errorf("Expected 1 diagnostic without tests, got %d", len(diags))
+	}
+
+	diags, err = CheckTree(root, true, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(diags) != 2 {
+		t.Errorf("Expected 2 diagnostics with tests, got %d", len(diags))
+	}
+
+	diags, err = CheckTree(root, false, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(diags) != 0 {
+		t.Errorf("Expected no diagnostics without recursion, got %d", len(diags))
+	}
+}
@@ -2,10 +2,14 @@ package dbutil
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"log"
 	"os"
+	"strings"
 	"sync"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -13,6 +17,11 @@ var (
 	// Shared test database connection for all integration tests
 	testDBPool *pgxpool.Pool
 	testDBOnce sync.Once
+
+	// Per-test-binary schema isolation, enabled with UseTestSchema
+	testSchemaEnabled bool
+	testSchemaSQL     string
+	testSchemaName    string
 )
 
 // GetTestConnection returns a shared test database connection, initializing it once
@@ -30,6 +39,7 @@ func GetTestConnection[T Querier](newQueriesFunc func(*pgxpool.Pool) T) *Connect
 		pool:    testDBPool,
 		queries: newQueriesFunc(testDBPool),
 		metrics: nil,
+		server:  &serverInfo{},
 	}
 }
 
@@ -52,6 +62,18 @@ func initTestDatabasePool() *pgxpool.Pool {
 	config.MaxConns = 5
 	config.MinConns = 1
 
+	// Isolate this test binary in its own schema so packages can share one database.
+	// The schema is searched first, followed by the URL's search_path (or public) so
+	// extensions and shared objects stay visible.
+	if testSchemaEnabled {
+		testSchemaName = newTestSchemaName()
+		searchPath := "public"
+		if existing := config.ConnConfig.RuntimeParams["search_path"]; existing != "" {
+			searchPath = existing
+		}
+		config.ConnConfig.RuntimeParams["search_path"] = testSchemaName + ", " + searchPath
+	}
+
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		log.Fatalf("Failed to connect to test database: %v", err)
@@ -62,18 +84,104 @@ func initTestDatabasePool() *pgxpool.Pool {
 		log.Fatalf("Failed to ping test database: %v", err)
 	}
 
+	if testSchemaEnabled {
+		if err := createTestSchema(ctx, pool); err != nil {
+			log.Fatalf("Failed to create test schema %s: %v", testSchemaName, err)
+		}
+		log.Printf("Test database pool initialized successfully in schema %s", testSchemaName)
+		return pool
+	}
+
 	log.Printf("Test database pool initialized successfully")
 	return pool
 }
 
+// newTestSchemaName returns a unique schema name for this test binary
+func newTestSchemaName() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("Failed to generate test schema name: %v", err)
+	}
+	return "dbutil_test_" + hex.EncodeToString(b)
+}
+
+// createTestSchema creates the per-binary schema and applies the test schema SQL into it
+func createTestSchema(ctx context.Context, pool *pgxpool.Pool) error {
+	if _, err := pool.Exec(ctx, "CREATE SCHEMA "+pgx.Identifier{testSchemaName}.Sanitize()); err != nil {
+		return err
+	}
+	if testSchemaSQL != "" {
+		// Exec without arguments uses the simple protocol, so multi-statement SQL is allowed
+		if _, err := pool.Exec(ctx, testSchemaSQL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseTestSchema makes the shared test connection run inside a uniquely-named schema
+// created for this test binary. The schema is put first on the connection's search_path and
+// schemaSQL (e.g. your migrations) is applied into it. Call it from TestMain before
+// any test obtains a connection, and call DropTestSchema after the tests finish.
+// RunTestsInSchema does both.
+func UseTestSchema(schemaSQL string) {
+	testSchemaEnabled = true
+	testSchemaSQL = schemaSQL
+}
+
+// TestSchemaName returns the schema created for this test binary, or "" if none
+func TestSchemaName() string {
+	return testSchemaName
+}
+
+// DropTestSchema drops the schema created for this test binary and closes the shared pool
+func DropTestSchema() {
+	DropTestSchemaContext(context.Background())
+}
+
+// DropTestSchemaContext is DropTestSchema using ctx
+func DropTestSchemaContext(ctx context.Context) {
+	if testDBPool == nil || testSchemaName == "" {
+		return
+	}
+
+	if _, err := testDBPool.Exec(ctx, "DROP SCHEMA IF EXISTS "+pgx.Identifier{testSchemaName}.Sanitize()+" CASCADE"); err != nil {
+		log.Printf("Warning: Failed to drop test schema %s: %v", testSchemaName, err)
+	}
+	testDBPool.Close()
+	testDBPool = nil
+}
+
+// TestMainRunner is an interface that matches *testing.M
+type TestMainRunner interface {
+	Run() int
+}
+
+// RunTestsInSchema runs the tests inside a per-binary schema and drops it afterwards.
+// Use it from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//	    os.Exit(dbutil.RunTestsInSchema(m, schemaSQL))
+//	}
+func RunTestsInSchema(m TestMainRunner, schemaSQL string) int {
+	UseTestSchema(schemaSQL)
+	code := m.Run()
+	DropTestSchema()
+	return code
+}
+
 // CleanupTestData executes cleanup SQL statements
 // This is a generic cleanup utility that takes SQL statements as parameters
 func CleanupTestData[T Querier](conn *Connection[T], sqlStatements ...string) {
+	CleanupTestDataContext(context.Background(), conn, sqlStatements...)
+}
+
+// CleanupTestDataContext is CleanupTestData using ctx
+func CleanupTestDataContext[T Querier](ctx context.Context, conn *Connection[T], sqlStatements ...string) {
 	if conn == nil {
 		return
 	}
 
-	ctx := context.Background()
 	pool := conn.GetDB()
 
 	for _, sql := range sqlStatements {
@@ -84,6 +192,74 @@ func CleanupTestData[T Querier](conn *Connection[T], sqlStatements ...string) {
 	}
 }
 
+// TruncateTables empties the given tables with TRUNCATE ... RESTART IDENTITY CASCADE.
+// Foreign key ordering is handled by CASCADE, and identity/serial sequences are reset
+// so each test starts from a clean state. Table names may be schema-qualified.
+func TruncateTables[T Querier](conn *Connection[T], tables ...string) error {
+	return TruncateTablesContext(context.Background(), conn, tables...)
+}
+
+// TruncateTablesContext is TruncateTables using ctx
+func TruncateTablesContext[T Querier](ctx context.Context, conn *Connection[T], tables ...string) error {
+	if conn == nil || len(tables) == 0 {
+		return nil
+	}
+
+	if _, err := conn.GetDB().Exec(ctx, truncateTablesSQL(tables)); err != nil {
+		return NewQueryError("", strings.Join(tables, ", "), "truncate", err)
+	}
+	return nil
+}
+
+// TruncateAllTables empties every table in the connection's current schema except
+// those listed in exclude (e.g. "schema_migrations").
+func TruncateAllTables[T Querier](conn *Connection[T], exclude ...string) error {
+	return TruncateAllTablesContext(context.Background(), conn, exclude...)
+}
+
+// TruncateAllTablesContext is TruncateAllTables using ctx
+func TruncateAllTablesContext[T Querier](ctx context.Context, conn *Connection[T], exclude ...string) error {
+	if conn == nil {
+		return nil
+	}
+
+	rows, err := conn.GetDB().Query(ctx, "SELECT tablename FROM pg_tables WHERE schemaname = current_schema() ORDER BY tablename")
+	if err != nil {
+		return NewQueryError("", "pg_tables", "query", err)
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return NewQueryError("", "pg_tables", "query", err)
+	}
+
+	return TruncateTablesContext(ctx, conn, excludeTables(tables, exclude)...)
+}
+
+// truncateTablesSQL builds a single TRUNCATE statement for the given tables
+func truncateTablesSQL(tables []string) string {
+	quoted := make([]string, len(tables))
+	for i, table := range tables {
+		quoted[i] = pgx.Identifier(strings.Split(table, ".")).Sanitize()
+	}
+	return "TRUNCATE TABLE " + strings.Join(quoted, ", ") + " RESTART IDENTITY CASCADE"
+}
+
+// excludeTables returns tables without the names listed in exclude
+func excludeTables(tables, exclude []string) []string {
+	skip := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		skip[name] = true
+	}
+
+	result := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if !skip[table] {
+			result = append(result, table)
+		}
+	}
+	return result
+}
+
 // RequireTestDB ensures a test database is available or skips the test
 func RequireTestDB[T Querier](t TestingT, newQueriesFunc func(*pgxpool.Pool) T) *Connection[T] {
 	conn := GetTestConnection(newQueriesFunc)